@@ -0,0 +1,101 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// aggregateCmd represents the aggregate command
+var aggregateCmd = &cobra.Command{
+	Use:   "aggregate",
+	Short: "aggregate a file of CIDRs into the minimal covering set",
+	Long: `Read a file of CIDRs (one per line) and print the aggregated set.
+Duplicate and overlapping entries are merged.  This is the batch form of
+summarize, e.g. for route tables exported from routers:
+
+	cidr aggregate --file routes.txt --output json
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		file, err := cmd.Flags().GetString("file")
+		if err != nil {
+			panic(err)
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+		maxmem, err := cmd.Flags().GetInt("maxmem")
+		if err != nil {
+			panic(err)
+		}
+		words, err := splitFlag(cmd)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		var in io.Reader = os.Stdin
+		if len(file) > 0 && file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			in = f
+		}
+
+		ranges, err := readRanges(in, maxmem, words)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		if err := writeBlocks(os.Stdout, summarize(ranges), output); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// write the blocks one per line, or as a list of strings
+func writeBlocks(w io.Writer, blocks []block, output string) error {
+	if output == "text" {
+		for _, b := range blocks {
+			fmt.Fprintf(w, "%s\n", b)
+		}
+		return nil
+	}
+
+	list := make([]string, len(blocks))
+	for i, b := range blocks {
+		list[i] = b.String()
+	}
+	return writeEncoded(w, list, output)
+}
+
+func init() {
+	RootCmd.AddCommand(aggregateCmd)
+
+	aggregateCmd.Flags().StringP("file", "f", "-", "file of CIDRs to aggregate ('-' for stdin)")
+	aggregateCmd.Flags().StringP("output", "o", "text", "output format: text, json or yaml")
+	aggregateCmd.Flags().Int("maxmem", 256, "refuse to buffer more than this many MiB of input (0 for no limit)")
+	addSplitFlag(aggregateCmd)
+}
@@ -0,0 +1,68 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAggregateOverlapping(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		// duplicates
+		{"10.0.0.0/24\n10.0.0.0/24\n", "10.0.0.0/24\n"},
+		// a block nested in another
+		{"10.0.0.0/16\n10.0.5.0/24\n", "10.0.0.0/16\n"},
+		// partially overlapping ranges
+		{"10.0.0.0-10.0.0.5\n10.0.0.4-10.0.0.7\n", "10.0.0.0/29\n"},
+		// adjacent halves, out of order
+		{"10.0.1.0/24\n10.0.0.0/24\n", "10.0.0.0/23\n"},
+		// overlapping, with a gap left between them
+		{"10.0.0.0/25\n10.0.0.64/26\n10.0.1.0/24\n", "10.0.0.0/25\n10.0.1.0/24\n"},
+	}
+
+	for _, tt := range tests {
+		ranges, err := readRanges(strings.NewReader(tt.input), 0, false)
+		if err != nil {
+			t.Errorf("readRanges(%q): %s", tt.input, err)
+			continue
+		}
+
+		var sb strings.Builder
+		if err := writeBlocks(&sb, summarize(ranges), "text"); err != nil {
+			t.Fatal(err)
+		}
+		if sb.String() != tt.want {
+			t.Errorf("aggregating %q gave %q, want %q", tt.input, sb.String(), tt.want)
+		}
+	}
+}
+
+func TestAggregateFileJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "routes.txt")
+	if err := os.WriteFile(path, []byte("10.0.0.0/24\n10.0.0.128/25\n10.0.1.0/24\n192.168.0.0/24\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out := runRoot(t, "aggregate", "--file", path, "--output", "json")
+	want := "[\n  \"10.0.0.0/23\",\n  \"192.168.0.0/24\"\n]\n"
+	if out != want {
+		t.Errorf("got %q, want %q", out, want)
+	}
+}
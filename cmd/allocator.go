@@ -0,0 +1,145 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"sync"
+)
+
+// Allocator hands out non-overlapping blocks from an IPv4 supernet.
+// It is safe for concurrent use.
+type Allocator struct {
+	mu        sync.Mutex
+	supernet  block
+	allocated []block // sorted by base address
+}
+
+// NewAllocator returns an Allocator for the supernet with nothing allocated.
+func NewAllocator(supernet netip.Prefix) (*Allocator, error) {
+	b, err := blockFromPrefix(supernet)
+	if err != nil {
+		return nil, err
+	}
+	return &Allocator{supernet: b}, nil
+}
+
+// Allocate reserves and returns the lowest free block with the given
+// prefix length.
+func (a *Allocator) Allocate(prefix int) (netip.Prefix, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if prefix < a.supernet.prefix || prefix > 32 {
+		return netip.Prefix{}, fmt.Errorf("/%d does not fit within %s", prefix, a.supernet)
+	}
+
+	b, ok := a.nextFree(prefix)
+	if !ok {
+		return netip.Prefix{}, fmt.Errorf("no free /%d remains in %s", prefix, a.supernet)
+	}
+
+	a.insert(b)
+	return b.netipPrefix(), nil
+}
+
+// Reserve marks a specific block as allocated, e.g. when restoring state.
+func (a *Allocator) Reserve(p netip.Prefix) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	b, err := blockFromPrefix(p)
+	if err != nil {
+		return err
+	}
+	if !a.supernet.contains(b) {
+		return fmt.Errorf("%s is not within %s", b, a.supernet)
+	}
+	for _, x := range a.allocated {
+		if x.span().overlaps(b.span()) {
+			return fmt.Errorf("%s overlaps the allocated block %s", b, x)
+		}
+	}
+
+	a.insert(b)
+	return nil
+}
+
+// Release returns a previously allocated block to the free pool.
+func (a *Allocator) Release(p netip.Prefix) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	b, err := blockFromPrefix(p)
+	if err != nil {
+		return err
+	}
+	for i, x := range a.allocated {
+		if x == b {
+			a.allocated = append(a.allocated[:i], a.allocated[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not allocated", b)
+}
+
+// Allocated returns the allocated blocks in address order.
+func (a *Allocator) Allocated() []netip.Prefix {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	result := make([]netip.Prefix, len(a.allocated))
+	for i, b := range a.allocated {
+		result[i] = b.netipPrefix()
+	}
+	return result
+}
+
+// find the lowest aligned block of the prefix length lying in a gap
+// between the allocations
+func (a *Allocator) nextFree(prefix int) (block, bool) {
+	size := uint64(1) << uint(32-prefix)
+	next := uint64(a.supernet.base)
+	end := uint64(a.supernet.last()) + 1
+
+	for i := 0; i <= len(a.allocated); i++ {
+		gapEnd := end
+		if i < len(a.allocated) {
+			gapEnd = uint64(a.allocated[i].base)
+		}
+
+		// round up to the block's alignment
+		start := (next + size - 1) &^ (size - 1)
+		if start+size <= gapEnd {
+			return block{base: uint32(start), prefix: prefix}, true
+		}
+
+		if i < len(a.allocated) {
+			next = uint64(a.allocated[i].last()) + 1
+		}
+	}
+	return block{}, false
+}
+
+// add the block to the allocations, keeping them sorted
+func (a *Allocator) insert(b block) {
+	i := sort.Search(len(a.allocated), func(i int) bool {
+		return a.allocated[i].base > b.base
+	})
+	a.allocated = append(a.allocated, block{})
+	copy(a.allocated[i+1:], a.allocated[i:])
+	a.allocated[i] = b
+}
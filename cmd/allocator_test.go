@@ -0,0 +1,154 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"net/netip"
+	"slices"
+	"sync"
+	"testing"
+)
+
+// fail unless no two of the prefixes overlap
+func checkDisjoint(t *testing.T, prefixes []netip.Prefix) {
+	t.Helper()
+	for i, p := range prefixes {
+		for _, q := range prefixes[i+1:] {
+			if p.Overlaps(q) {
+				t.Errorf("%s overlaps %s", p, q)
+			}
+		}
+	}
+}
+
+func prefixStrings(prefixes []netip.Prefix) []string {
+	s := make([]string, len(prefixes))
+	for i, p := range prefixes {
+		s[i] = p.String()
+	}
+	return s
+}
+
+func TestAllocate(t *testing.T) {
+	a, err := NewAllocator(netip.MustParsePrefix("10.0.0.0/24"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []netip.Prefix
+	for _, prefix := range []int{26, 28, 26, 28} {
+		p, err := a.Allocate(prefix)
+		if err != nil {
+			t.Fatalf("Allocate(%d): %s", prefix, err)
+		}
+		got = append(got, p)
+	}
+
+	// each block is the lowest aligned one free
+	want := []string{"10.0.0.0/26", "10.0.0.64/28", "10.0.0.128/26", "10.0.0.80/28"}
+	if !slices.Equal(prefixStrings(got), want) {
+		t.Errorf("allocated %v, want %v", prefixStrings(got), want)
+	}
+	checkDisjoint(t, a.Allocated())
+
+	if p, err := a.Allocate(25); err == nil {
+		t.Errorf("allocated %s, but no /25 is free", p)
+	}
+	if p, err := a.Allocate(23); err == nil {
+		t.Errorf("allocated %s, larger than the supernet", p)
+	}
+}
+
+func TestRelease(t *testing.T) {
+	a, err := NewAllocator(netip.MustParsePrefix("10.0.0.0/24"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 4 {
+		if _, err := a.Allocate(26); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if p, err := a.Allocate(26); err == nil {
+		t.Fatalf("allocated %s from a full supernet", p)
+	}
+
+	second := netip.MustParsePrefix("10.0.0.64/26")
+	if err := a.Release(second); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Release(second); err == nil {
+		t.Error("released a block twice")
+	}
+
+	// the released block is handed out again
+	p, err := a.Allocate(26)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != second {
+		t.Errorf("allocated %s, want the released %s", p, second)
+	}
+	checkDisjoint(t, a.Allocated())
+}
+
+func TestReserve(t *testing.T) {
+	a, err := NewAllocator(netip.MustParsePrefix("10.0.0.0/24"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Reserve(netip.MustParsePrefix("10.0.0.0/25")); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Reserve(netip.MustParsePrefix("10.0.0.64/26")); err == nil {
+		t.Error("reserved a block overlapping another")
+	}
+	if err := a.Reserve(netip.MustParsePrefix("10.0.1.0/26")); err == nil {
+		t.Error("reserved a block outside of the supernet")
+	}
+
+	p, err := a.Allocate(26)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.String() != "10.0.0.128/26" {
+		t.Errorf("allocated %s, want 10.0.0.128/26", p)
+	}
+}
+
+func TestAllocateConcurrently(t *testing.T) {
+	a, err := NewAllocator(netip.MustParsePrefix("10.0.0.0/16"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for range 64 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := a.Allocate(24); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	allocated := a.Allocated()
+	if len(allocated) != 64 {
+		t.Errorf("allocated %d blocks, want 64", len(allocated))
+	}
+	checkDisjoint(t, allocated)
+}
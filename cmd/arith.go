@@ -0,0 +1,108 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+	Use:   "add <address> <n>",
+	Short: "add n to an address",
+	Long: `Treat the address as a 32 bit integer and add n to it.  Example:
+
+	cidr add 10.0.0.250 10
+
+returns
+
+	10.0.1.4
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runArithmetic(cmd, args, 1)
+	},
+}
+
+// subCmd represents the sub command
+var subCmd = &cobra.Command{
+	Use:   "sub <address> <n>",
+	Short: "subtract n from an address",
+	Long: `Treat the address as a 32 bit integer and subtract n from it.  Example:
+
+	cidr sub 10.0.1.4 10
+
+returns
+
+	10.0.0.250
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		runArithmetic(cmd, args, -1)
+	},
+}
+
+// shared Run for add & sub; sign is +1 or -1
+func runArithmetic(cmd *cobra.Command, args []string, sign int64) {
+
+	if len(args) != 2 {
+		cmd.Usage()
+		return
+	}
+
+	wrap, err := cmd.Flags().GetBool("wrap")
+	if err != nil {
+		panic(err)
+	}
+
+	addr, err := parseIPv4(args[0])
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		os.Exit(1)
+	}
+
+	n, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		fmt.Printf("error parsing '%s' -- %s\n", args[1], err)
+		os.Exit(1)
+	}
+
+	result, err := offsetIPv4(addr, sign*n, wrap)
+	if err != nil {
+		fmt.Printf("%s\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("%s\n", formatIPv4(result))
+}
+
+// add n to the address, wrapping around the 32 bit space or failing
+// if the result would fall outside of it
+func offsetIPv4(addr uint32, n int64, wrap bool) (uint32, error) {
+	result := int64(addr) + n
+	if !wrap && (result < 0 || result > 0xffffffff) {
+		return 0, fmt.Errorf("%s %+d is outside of the IPv4 address space (use --wrap to wrap around)",
+			formatIPv4(addr), n)
+	}
+	return uint32(result), nil
+}
+
+func init() {
+	RootCmd.AddCommand(addCmd)
+	RootCmd.AddCommand(subCmd)
+
+	addCmd.Flags().Bool("wrap", false, "wrap around the address space instead of failing")
+	subCmd.Flags().Bool("wrap", false, "wrap around the address space instead of failing")
+}
@@ -0,0 +1,64 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestOffsetIPv4(t *testing.T) {
+	tests := []struct {
+		addr string
+		n    int64
+		wrap bool
+		want string
+	}{
+		{"10.0.0.250", 10, false, "10.0.1.4"},
+		{"10.0.0.255", 1, false, "10.0.1.0"},
+		{"10.0.255.255", 1, false, "10.1.0.0"},
+		{"10.255.255.255", 1, false, "11.0.0.0"},
+		{"10.0.1.4", -10, false, "10.0.0.250"},
+		{"10.1.0.0", -1, false, "10.0.255.255"},
+		{"255.255.255.255", 1, true, "0.0.0.0"},
+		{"0.0.0.0", -1, true, "255.255.255.255"},
+	}
+
+	for _, tt := range tests {
+		addr, err := parseIPv4(tt.addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := offsetIPv4(addr, tt.n, tt.wrap)
+		if err != nil {
+			t.Errorf("offsetIPv4(%s, %d): %s", tt.addr, tt.n, err)
+			continue
+		}
+		if formatIPv4(got) != tt.want {
+			t.Errorf("offsetIPv4(%s, %d) = %s, want %s", tt.addr, tt.n, formatIPv4(got), tt.want)
+		}
+	}
+}
+
+func TestOffsetIPv4Overflow(t *testing.T) {
+	for _, tt := range []struct {
+		addr uint32
+		n    int64
+	}{
+		{0xffffffff, 1},
+		{0, -1},
+		{0xfffffff0, 0x100},
+	} {
+		if got, err := offsetIPv4(tt.addr, tt.n, false); err == nil {
+			t.Errorf("offsetIPv4(%s, %d) = %s, expected an overflow error", formatIPv4(tt.addr), tt.n, formatIPv4(got))
+		}
+	}
+}
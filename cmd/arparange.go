@@ -0,0 +1,88 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// toArpaRangeCmd represents the to-arpa-range command
+var toArpaRangeCmd = &cobra.Command{
+	Use:   "to-arpa-range <cidr>",
+	Short: "list the in-addr.arpa zones covering a network",
+	Long: `List the reverse DNS zones to delegate for an IPv4 network.  A prefix
+on an octet boundary is a single zone; a shorter one is split into the
+zones of the next octet boundary, and one longer than /24 is named in
+the classless form of RFC 2317.  Example:
+
+	cidr to-arpa-range 172.16.16.0/22
+
+returns
+
+	16.16.172.in-addr.arpa
+	17.16.172.in-addr.arpa
+	18.16.172.in-addr.arpa
+	19.16.172.in-addr.arpa
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		network, err := parseBlock(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		for _, zone := range arpaZones(network) {
+			fmt.Printf("%s\n", zone)
+		}
+	},
+}
+
+// the in-addr.arpa zone of the first prefix/8 octets of addr
+func arpaZone(addr uint32, prefix int) string {
+	labels := []string{"in-addr.arpa"}
+	for i := 0; i < prefix/8; i++ {
+		labels = append([]string{fmt.Sprintf("%d", addr>>(24-8*uint(i))&0x0ff)}, labels...)
+	}
+	return strings.Join(labels, ".")
+}
+
+// the zones covering the network
+func arpaZones(network block) []string {
+	if network.prefix > 24 {
+		// RFC 2317: name the block by its first address within the /24
+		return []string{fmt.Sprintf("%d/%d.%s", network.base&0x0ff, network.prefix, arpaZone(network.base, 24))}
+	}
+
+	aligned := (network.prefix + 7) / 8 * 8
+	var zones []string
+	enumerateSubnets(network, aligned, page{}, func(i uint64, sub block) bool {
+		zones = append(zones, arpaZone(sub.base, aligned))
+		return true
+	})
+	return zones
+}
+
+func init() {
+	RootCmd.AddCommand(toArpaRangeCmd)
+}
@@ -0,0 +1,58 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestArpaZones(t *testing.T) {
+	var slash20 []string
+	for i := 0; i < 16; i++ {
+		slash20 = append(slash20, fmt.Sprintf("%d.0.10.in-addr.arpa", i))
+	}
+
+	tests := []struct {
+		network string
+		want    []string
+	}{
+		{"192.168.1.0/24", []string{"1.168.192.in-addr.arpa"}},
+		{"10.0.0.0/20", slash20},
+		{"192.168.1.16/28", []string{"16/28.1.168.192.in-addr.arpa"}},
+		{"192.168.1.0/25", []string{"0/25.1.168.192.in-addr.arpa"}},
+		{"172.16.16.0/22", []string{
+			"16.16.172.in-addr.arpa", "17.16.172.in-addr.arpa",
+			"18.16.172.in-addr.arpa", "19.16.172.in-addr.arpa",
+		}},
+		{"10.0.0.0/16", []string{"0.10.in-addr.arpa"}},
+		{"10.0.0.0/8", []string{"10.in-addr.arpa"}},
+		{"0.0.0.0/0", []string{"in-addr.arpa"}},
+	}
+
+	for _, tt := range tests {
+		got := arpaZones(parseBlocks(t, tt.network)[0])
+		if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+			t.Errorf("arpaZones(%s) = %v, want %v", tt.network, got, tt.want)
+		}
+	}
+}
+
+func TestToArpaRangeCommand(t *testing.T) {
+	want := "16.16.172.in-addr.arpa\n17.16.172.in-addr.arpa\n18.16.172.in-addr.arpa\n19.16.172.in-addr.arpa\n"
+	if got := runRoot(t, "to-arpa-range", "172.16.16.0/22"); got != want {
+		t.Errorf("to-arpa-range 172.16.16.0/22 printed %q, want %q", got, want)
+	}
+}
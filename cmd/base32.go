@@ -0,0 +1,118 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/base32"
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// encodeBase32Cmd represents the encode-base32 command
+var encodeBase32Cmd = &cobra.Command{
+	Use:   "encode-base32 <address>",
+	Short: "encode an address as compact, URL safe base32",
+	Long: `Encode the bytes of an IPv4 or IPv6 address as unpadded base32
+(RFC 4648), e.g. for embedding in short tokens.  decode-base32 reverses
+it exactly.  Example:
+
+	cidr encode-base32 172.16.16.65
+
+returns
+
+	VQIBAQI
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		str, err := encodeBase32(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s\n", str)
+	},
+}
+
+// decodeBase32Cmd represents the decode-base32 command
+var decodeBase32Cmd = &cobra.Command{
+	Use:   "decode-base32 <token>",
+	Short: "decode an address encoded by encode-base32",
+	Long: `Decode a base32 token made by encode-base32 back into its address.
+Seven characters decode to an IPv4 address and 26 to an IPv6 address;
+case is ignored.  Example:
+
+	cidr decode-base32 VQIBAQI
+
+returns
+
+	172.16.16.65
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		addr, err := decodeBase32(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s\n", addr)
+	},
+}
+
+// unpadded base32, so every address encodes to a fixed length
+var addrEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// encode the address's bytes as base32
+func encodeBase32(address string) (string, error) {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return "", fmt.Errorf("'%s' is not a valid address", address)
+	}
+	if len(addr.Zone()) > 0 {
+		return "", fmt.Errorf("'%s' has a zone, which can't be encoded", address)
+	}
+	return addrEncoding.EncodeToString(addr.AsSlice()), nil
+}
+
+// decode a base32 token into a 4 or 16 byte address
+func decodeBase32(token string) (netip.Addr, error) {
+	b, err := addrEncoding.DecodeString(strings.ToUpper(strings.TrimSpace(token)))
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("'%s' is not valid base32 -- %s", token, err)
+	}
+
+	addr, ok := netip.AddrFromSlice(b)
+	if !ok {
+		return netip.Addr{}, fmt.Errorf("'%s' decodes to %d bytes, expected 4 or 16", token, len(b))
+	}
+	return addr, nil
+}
+
+func init() {
+	RootCmd.AddCommand(encodeBase32Cmd)
+	RootCmd.AddCommand(decodeBase32Cmd)
+}
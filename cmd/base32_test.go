@@ -0,0 +1,66 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestBase32(t *testing.T) {
+	tests := []struct {
+		address, token string
+	}{
+		{"172.16.16.65", "VQIBAQI"},
+		{"0.0.0.0", "AAAAAAA"},
+		{"255.255.255.255", "777777Y"},
+		{"2001:db8::1", "EAAQ3OAAAAAAAAAAAAAAAAAAAE"},
+	}
+
+	for _, tt := range tests {
+		token, err := encodeBase32(tt.address)
+		if err != nil {
+			t.Errorf("encodeBase32(%q): %s", tt.address, err)
+			continue
+		}
+		if token != tt.token {
+			t.Errorf("encodeBase32(%q) = %s, want %s", tt.address, token, tt.token)
+		}
+
+		addr, err := decodeBase32(token)
+		if err != nil {
+			t.Errorf("decodeBase32(%q): %s", token, err)
+			continue
+		}
+		if addr.String() != tt.address {
+			t.Errorf("decodeBase32(%q) = %s, want %s", token, addr, tt.address)
+		}
+	}
+
+	// decoding ignores case and surrounding space
+	addr, err := decodeBase32(" vqibaqi\n")
+	if err != nil || addr.String() != "172.16.16.65" {
+		t.Errorf("decodeBase32 of a lowercase token = %s, %v", addr, err)
+	}
+}
+
+func TestBase32Errors(t *testing.T) {
+	for _, address := range []string{"fe80::1%eth0", "10.0.0", ""} {
+		if token, err := encodeBase32(address); err == nil {
+			t.Errorf("encodeBase32(%q) = %s, expected an error", address, token)
+		}
+	}
+	for _, token := range []string{"VQIBA", "VQIBAQI1", "AAAAAAAAAAAAAAAA"} {
+		if addr, err := decodeBase32(token); err == nil {
+			t.Errorf("decodeBase32(%q) = %s, expected an error", token, addr)
+		}
+	}
+}
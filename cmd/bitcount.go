@@ -0,0 +1,78 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/bits"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// bitcountCmd represents the bitcount command
+var bitcountCmd = &cobra.Command{
+	Use:   "bitcount <address>",
+	Short: "count the set bits of an IPv4 address or netmask",
+	Long: `Print the number of set bits in an IPv4 address or netmask.  For a
+netmask this is its prefix length, provided the mask is contiguous.  An
+address which looks like a netmask, leading with 255 or made only of
+octets a netmask can have, but isn't contiguous draws a warning on
+stderr; an ordinary address such as 10.1.2.3 doesn't.  Example:
+
+	cidr bitcount 255.255.240.0
+
+returns
+
+	20
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		addr, err := parseIPv4(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("%d\n", bits.OnesCount32(addr))
+		if _, err := netmaskPrefix(args[0]); err != nil && looksLikeNetmaskAddr(addr) {
+			fmt.Fprintf(os.Stderr, "warning: %s, so the count is not a prefix length\n", err)
+		}
+	},
+}
+
+// true if the address reads as an attempt at a netmask: its first octet
+// is 255, or each octet is one a netmask can have, e.g. 255.0.255.0
+func looksLikeNetmaskAddr(addr uint32) bool {
+	if addr>>24 == 0xff {
+		return true
+	}
+	for shift := 24; shift >= 0; shift -= 8 {
+		switch addr >> uint(shift) & 0xff {
+		case 0, 128, 192, 224, 240, 248, 252, 254, 255:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func init() {
+	RootCmd.AddCommand(bitcountCmd)
+}
@@ -0,0 +1,81 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// run f, returning what it wrote to stderr
+func captureStderr(t *testing.T, f func()) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	f()
+	os.Stderr = stderr
+	w.Close()
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestBitcount(t *testing.T) {
+	tests := []struct {
+		address string
+		count   string
+		warning string // a substring of the warning, or empty for none
+	}{
+		{"255.255.255.255", "32", ""},
+		{"255.255.240.0", "20", ""},
+		{"255.0.0.0", "8", ""},
+		{"0.0.0.0", "0", ""},
+		{"255.0.255.0", "16", "the netmask '255.0.255.0' is not contiguous"},
+		{"0.255.255.255", "24", "not contiguous"},
+		{"255.255.255.254", "31", ""},
+		{"255.255.255.253", "31", "not contiguous"},
+		{"255.1.2.3", "12", "not contiguous"},
+		// ordinary addresses aren't netmasks, so aren't warned about
+		{"10.1.2.3", "6", ""},
+		{"192.168.1.1", "7", ""},
+		{"172.16.0.1", "6", ""},
+	}
+
+	for _, tt := range tests {
+		var out string
+		warning := captureStderr(t, func() { out = runRoot(t, "bitcount", tt.address) })
+		if out != tt.count+"\n" {
+			t.Errorf("bitcount %s printed %q, want %s", tt.address, out, tt.count)
+		}
+		if tt.warning == "" && warning != "" {
+			t.Errorf("bitcount %s warned %q", tt.address, warning)
+		}
+		if !strings.Contains(warning, tt.warning) {
+			t.Errorf("bitcount %s warned %q, want %q", tt.address, warning, tt.warning)
+		}
+		if tt.warning != "" && !strings.HasPrefix(warning, "warning: ") {
+			t.Errorf("bitcount %s didn't warn", tt.address)
+		}
+	}
+}
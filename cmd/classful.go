@@ -0,0 +1,88 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// classfulCmd represents the classful command
+var classfulCmd = &cobra.Command{
+	Use:   "classful <address>",
+	Short: "report the legacy class of an address",
+	Long: `Report the pre-CIDR class of an address, determined from its first
+octet, along with the class's natural mask and network.  Classes D
+(multicast) and E (reserved) have no natural mask.  Example:
+
+	cidr classful 192.168.1.1
+
+returns
+
+	class C, natural mask /24, network 192.168.1.0/24
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		addr, err := parseIPv4(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		class, prefix := classOf(addr)
+		if prefix < 0 {
+			if resultOnly {
+				fmt.Printf("%s\n", class)
+				return
+			}
+			fmt.Printf("class %s, no natural mask\n", class)
+			return
+		}
+
+		network := block{base: addr & prefixMask(prefix), prefix: prefix}
+		if resultOnly {
+			fmt.Printf("%s\n", network)
+			return
+		}
+		fmt.Printf("class %s, natural mask /%d, network %s\n", class, prefix, network)
+	},
+}
+
+// return the class of the address and its natural prefix length,
+// or -1 for the classes without one
+func classOf(addr uint32) (string, int) {
+	first := addr >> 24
+	switch {
+	case first < 128:
+		return "A", 8
+	case first < 192:
+		return "B", 16
+	case first < 224:
+		return "C", 24
+	case first < 240:
+		return "D (multicast)", -1
+	}
+	return "E (reserved)", -1
+}
+
+func init() {
+	RootCmd.AddCommand(classfulCmd)
+}
@@ -0,0 +1,58 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestClassOf(t *testing.T) {
+	tests := []struct {
+		addr   string
+		class  string
+		prefix int
+	}{
+		{"0.0.0.0", "A", 8},
+		{"10.1.2.3", "A", 8},
+		{"127.255.255.255", "A", 8},
+		{"128.0.0.0", "B", 16},
+		{"172.16.5.4", "B", 16},
+		{"191.255.0.1", "B", 16},
+		{"192.0.0.0", "C", 24},
+		{"192.168.1.1", "C", 24},
+		{"223.255.255.255", "C", 24},
+		{"224.0.0.1", "D (multicast)", -1},
+		{"239.255.255.255", "D (multicast)", -1},
+		{"240.0.0.0", "E (reserved)", -1},
+		{"255.255.255.255", "E (reserved)", -1},
+	}
+
+	for _, tt := range tests {
+		addr, err := parseIPv4(tt.addr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		class, prefix := classOf(addr)
+		if class != tt.class || prefix != tt.prefix {
+			t.Errorf("classOf(%s) = %s /%d, want %s /%d", tt.addr, class, prefix, tt.class, tt.prefix)
+		}
+	}
+}
+
+func TestClassfulNetwork(t *testing.T) {
+	if got := runRoot(t, "classful", "192.168.1.1"); got != "class C, natural mask /24, network 192.168.1.0/24\n" {
+		t.Errorf("got %q", got)
+	}
+	if got := runRoot(t, "classful", "172.16.5.4"); got != "class B, natural mask /16, network 172.16.0.0/16\n" {
+		t.Errorf("got %q", got)
+	}
+}
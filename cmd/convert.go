@@ -0,0 +1,140 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/bits"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// convertCmd represents the convert command
+var convertCmd = &cobra.Command{
+	Use:   "convert <address>",
+	Short: "convert an IPv4 address between notations",
+	Long: `Convert an IPv4 address between dotted, integer, hex and binary
+notation.  Hex is written with a 0x prefix and binary as four dotted
+octets; either prefix or dots may be omitted on input.  Integer and
+hex are big endian (network order) unless --endianness little.  Example:
+
+	cidr convert --from dotted --to integer 172.16.16.65
+
+returns
+
+	2886733889
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		from, err := cmd.Flags().GetString("from")
+		if err != nil {
+			panic(err)
+		}
+		to, err := cmd.Flags().GetString("to")
+		if err != nil {
+			panic(err)
+		}
+
+		endianness, err := cmd.Flags().GetString("endianness")
+		if err != nil {
+			panic(err)
+		}
+		littleEndian, err := parseEndianness(endianness)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		addr, err := parseNotation(args[0], from)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		if littleEndian && isIntegerNotation(from) {
+			addr = bits.ReverseBytes32(addr)
+		}
+		if littleEndian && isIntegerNotation(to) {
+			addr = bits.ReverseBytes32(addr)
+		}
+		str, err := formatNotation(addr, to)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s\n", str)
+	},
+}
+
+// true if the notation writes the address as a single number, so
+// --endianness applies to it
+func isIntegerNotation(notation string) bool {
+	return notation == "integer" || notation == "hex"
+}
+
+// parse an address written in the named notation
+func parseNotation(s, notation string) (uint32, error) {
+	s = strings.TrimSpace(s)
+
+	var text string
+	var base int
+	switch notation {
+	case "dotted":
+		return parseIPv4(s)
+	case "integer":
+		text, base = s, 10
+	case "hex":
+		text, base = strings.TrimPrefix(strings.TrimPrefix(s, "0x"), "0X"), 16
+	case "binary":
+		text, base = strings.ReplaceAll(strings.TrimPrefix(s, "0b"), ".", ""), 2
+	default:
+		return 0, fmt.Errorf("unknown notation '%s', expected dotted, integer, hex or binary", notation)
+	}
+
+	addr, err := strconv.ParseUint(text, base, 32)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a valid %s IPv4 address", s, notation)
+	}
+	return uint32(addr), nil
+}
+
+// format an address in the named notation
+func formatNotation(addr uint32, notation string) (string, error) {
+	switch notation {
+	case "dotted":
+		return formatIPv4(addr), nil
+	case "integer":
+		return strconv.FormatUint(uint64(addr), 10), nil
+	case "hex":
+		return fmt.Sprintf("0x%08x", addr), nil
+	case "binary":
+		return fmt.Sprintf("%08b.%08b.%08b.%08b", addr>>24, (addr>>16)&0x0ff, (addr>>8)&0x0ff, addr&0x0ff), nil
+	}
+	return "", fmt.Errorf("unknown notation '%s', expected dotted, integer, hex or binary", notation)
+}
+
+func init() {
+	RootCmd.AddCommand(convertCmd)
+
+	convertCmd.Flags().String("from", "dotted", "the notation of the input: dotted, integer, hex or binary")
+	convertCmd.Flags().String("to", "integer", "the notation to print: dotted, integer, hex or binary")
+	convertCmd.Flags().String("endianness", "big", "the byte order of integer and hex notations: big (network order) or little")
+}
@@ -0,0 +1,110 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+// 172.16.16.65 in each notation
+var notations = map[string]string{
+	"dotted":  "172.16.16.65",
+	"integer": "2886733889",
+	"hex":     "0xac101041",
+	"binary":  "10101100.00010000.00010000.01000001",
+}
+
+func TestConvertEachDirection(t *testing.T) {
+	for from, input := range notations {
+		addr, err := parseNotation(input, from)
+		if err != nil {
+			t.Errorf("parseNotation(%q, %s): %s", input, from, err)
+			continue
+		}
+		for to, want := range notations {
+			got, err := formatNotation(addr, to)
+			if err != nil {
+				t.Errorf("formatNotation(%s): %s", to, err)
+				continue
+			}
+			if got != want {
+				t.Errorf("converting %s from %s to %s gave %s, want %s", input, from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestParseNotationVariants(t *testing.T) {
+	tests := []struct {
+		input, notation string
+	}{
+		{"ac101041", "hex"},
+		{"0XAC101041", "hex"},
+		{"10101100000100000001000001000001", "binary"},
+		{"0b10101100000100000001000001000001", "binary"},
+		{" 2886733889 ", "integer"},
+	}
+
+	for _, tt := range tests {
+		addr, err := parseNotation(tt.input, tt.notation)
+		if err != nil {
+			t.Errorf("parseNotation(%q, %s): %s", tt.input, tt.notation, err)
+			continue
+		}
+		if formatIPv4(addr) != "172.16.16.65" {
+			t.Errorf("parseNotation(%q, %s) = %s, want 172.16.16.65", tt.input, tt.notation, formatIPv4(addr))
+		}
+	}
+
+	for _, tt := range []struct{ input, notation string }{
+		{"4294967296", "integer"},
+		{"0x1ac101041", "hex"},
+		{"102", "binary"},
+		{"172.16.16", "dotted"},
+		{"172.16.16.65", "octal"},
+	} {
+		if addr, err := parseNotation(tt.input, tt.notation); err == nil {
+			t.Errorf("parseNotation(%q, %s) = %s, expected an error", tt.input, tt.notation, formatIPv4(addr))
+		}
+	}
+}
+
+// 172.16.16.65 is 0xac101041 in network order and 0x411010ac little endian
+func TestEndianness(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"convert", "172.16.16.65"}, "2886733889"},
+		{[]string{"convert", "--endianness", "big", "172.16.16.65"}, "2886733889"},
+		{[]string{"convert", "--endianness", "little", "172.16.16.65"}, "1091571884"},
+		{[]string{"convert", "--endianness", "little", "--to", "hex", "172.16.16.65"}, "0x411010ac"},
+		{[]string{"convert", "--endianness", "little", "--from", "integer", "--to", "dotted", "1091571884"}, "172.16.16.65"},
+		{[]string{"convert", "--endianness", "little", "--from", "hex", "--to", "integer", "0x411010ac"}, "1091571884"},
+		// dotted and binary are unaffected
+		{[]string{"convert", "--endianness", "little", "--to", "binary", "172.16.16.65"}, "10101100.00010000.00010000.01000001"},
+		{[]string{"--mask", "12.8.6.6", "--within", "172.16.0.0", "--integer", "0.1.1.1"}, "2886733889"},
+		{[]string{"--mask", "12.8.6.6", "--within", "172.16.0.0", "--integer", "--endianness", "little", "0.1.1.1"}, "1091571884"},
+		{[]string{"--mask", "8.8.8.8", "--integer-input", "2886733889"}, "172.16.16.65"},
+		{[]string{"--mask", "8.8.8.8", "--integer-input", "--endianness", "little", "1091571884"}, "172.16.16.65"},
+	}
+
+	for _, tt := range tests {
+		if got := runRoot(t, tt.args...); got != tt.want+"\n" {
+			t.Errorf("cidr %v printed %q, want %s", tt.args, got, tt.want)
+		}
+	}
+
+	if _, err := parseEndianness("middle"); err == nil {
+		t.Errorf("parseEndianness accepted 'middle'")
+	}
+}
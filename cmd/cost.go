@@ -0,0 +1,141 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// costCmd represents the cost command
+var costCmd = &cobra.Command{
+	Use:   "cost --within <supernet> [--allocated <cidr>] [cidr...]",
+	Short: "report how much of a supernet is allocated",
+	Long: `Report the share of the supernet consumed by the allocations, given
+with --allocated or as arguments.  Allocations must lie within the
+supernet and may not overlap.  Example:
+
+	cidr cost --within 10.0.0.0/16 --allocated 10.0.0.0/24 10.0.1.0/24
+
+returns
+
+	512 of 65536 addresses allocated (0.78%)
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		within, err := cmd.Flags().GetString("within")
+		if err != nil {
+			panic(err)
+		}
+		allocated, err := cmd.Flags().GetStringSlice("allocated")
+		if err != nil {
+			panic(err)
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+		group, err := cmd.Flags().GetBool("group-digits")
+		if err != nil {
+			panic(err)
+		}
+
+		c, err := cost(within, append(allocated, args...))
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		if output == "text" && resultOnly {
+			fmt.Printf("%s\n", c.Percent)
+			return
+		}
+		if output == "text" {
+			allocated, total := c.Allocated.String(), c.Total.String()
+			if group {
+				allocated, total = groupDigits(allocated), groupDigits(total)
+			}
+			fmt.Printf("%s of %s addresses allocated (%s%%)\n", allocated, total, c.Percent)
+			return
+		}
+		if err := writeEncoded(os.Stdout, c, output); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// the utilization of a supernet
+type utilization struct {
+	Total     integer `json:"total" yaml:"total"`
+	Allocated integer `json:"allocated" yaml:"allocated"`
+	Percent   string  `json:"percent" yaml:"percent"`
+}
+
+// the number of addresses in a prefix
+func prefixSize(p netip.Prefix) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(p.Addr().BitLen()-p.Bits()))
+}
+
+// sum the sizes of the allocations and compare them to the supernet's
+func cost(within string, allocations []string) (utilization, error) {
+	supernet, err := netip.ParsePrefix(within)
+	if err != nil {
+		return utilization{}, fmt.Errorf("'%s' is not a valid CIDR", within)
+	}
+	supernet = supernet.Masked()
+
+	var prefixes []netip.Prefix
+	sum := new(big.Int)
+	for _, s := range allocations {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return utilization{}, fmt.Errorf("'%s' is not a valid CIDR", s)
+		}
+		p = p.Masked()
+		if p.Bits() < supernet.Bits() || !supernet.Contains(p.Addr()) {
+			return utilization{}, fmt.Errorf("%s is not within %s", p, supernet)
+		}
+		for _, o := range prefixes {
+			if o.Overlaps(p) {
+				return utilization{}, fmt.Errorf("%s overlaps %s", p, o)
+			}
+		}
+		prefixes = append(prefixes, p)
+		sum.Add(sum, prefixSize(p))
+	}
+
+	total := prefixSize(supernet)
+	percent := new(big.Rat).SetFrac(new(big.Int).Mul(sum, big.NewInt(100)), total)
+
+	return utilization{
+		Total:     integer{total},
+		Allocated: integer{sum},
+		Percent:   percent.FloatString(2),
+	}, nil
+}
+
+func init() {
+	RootCmd.AddCommand(costCmd)
+
+	costCmd.Flags().StringP("within", "w", "", "the supernet, e.g. 10.0.0.0/16")
+	costCmd.Flags().StringSlice("allocated", nil, "an allocated CIDR; may be repeated or comma separated")
+	costCmd.Flags().StringP("output", "o", "text", "output format: text, json or yaml")
+	costCmd.Flags().Bool("group-digits", false, "separate the digits of text output counts with commas, e.g. 16,777,216")
+	costCmd.MarkFlagRequired("within")
+}
@@ -0,0 +1,64 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestCost(t *testing.T) {
+	tests := []struct {
+		within      string
+		allocations []string
+		allocated   string
+		total       string
+		percent     string
+	}{
+		{"10.0.0.0/16", []string{"10.0.0.0/24", "10.0.1.0/24"}, "512", "65536", "0.78"},
+		{"10.0.0.0/24", []string{"10.0.0.0/25"}, "128", "256", "50.00"},
+		{"10.0.0.0/24", []string{"10.0.0.0/25", "10.0.0.128/26", "10.0.0.192/27"}, "224", "256", "87.50"},
+		{"10.0.0.0/24", nil, "0", "256", "0.00"},
+		{"10.0.0.0/24", []string{"10.0.0.0/24"}, "256", "256", "100.00"},
+		{"2001:db8::/32", []string{"2001:db8::/33"}, "39614081257132168796771975168", "79228162514264337593543950336", "50.00"},
+	}
+
+	for _, tt := range tests {
+		got, err := cost(tt.within, tt.allocations)
+		if err != nil {
+			t.Errorf("cost(%s, %v): %s", tt.within, tt.allocations, err)
+			continue
+		}
+		if got.Allocated.String() != tt.allocated || got.Total.String() != tt.total || got.Percent != tt.percent {
+			t.Errorf("cost(%s, %v) = %s of %s (%s%%), want %s of %s (%s%%)", tt.within, tt.allocations,
+				got.Allocated, got.Total, got.Percent, tt.allocated, tt.total, tt.percent)
+		}
+	}
+}
+
+func TestCostErrors(t *testing.T) {
+	tests := []struct {
+		within      string
+		allocations []string
+	}{
+		{"10.0.0.0/24", []string{"10.0.1.0/25"}},
+		{"10.0.0.0/24", []string{"10.0.0.0/23"}},
+		{"10.0.0.0/24", []string{"10.0.0.0/25", "10.0.0.64/26"}},
+		{"10.0.0.0", []string{"10.0.0.0/25"}},
+		{"10.0.0.0/24", []string{"bogus"}},
+	}
+
+	for _, tt := range tests {
+		if _, err := cost(tt.within, tt.allocations); err == nil {
+			t.Errorf("cost(%s, %v) expected an error", tt.within, tt.allocations)
+		}
+	}
+}
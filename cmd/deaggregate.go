@@ -0,0 +1,88 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// deaggregateCmd represents the deaggregate command
+var deaggregateCmd = &cobra.Command{
+	Use:   "deaggregate <cidr>",
+	Short: "split a CIDR into its component prefixes of a size",
+	Long: `Split the network into the blocks of the prefix length given by --into,
+the opposite of aggregate.  The new prefix must be longer than the
+network's.  Use --limit to cap the number of blocks printed.  Example:
+
+	cidr deaggregate --into /24 10.0.0.0/22
+
+returns
+
+	10.0.0.0/24
+	10.0.1.0/24
+	10.0.2.0/24
+	10.0.3.0/24
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		into, err := cmd.Flags().GetString("into")
+		if err != nil {
+			panic(err)
+		}
+		limit, err := cmd.Flags().GetUint64("limit")
+		if err != nil {
+			panic(err)
+		}
+
+		network, err := parseBlock(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		prefix, err := parsePrefixLen(into)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		if prefix <= network.prefix {
+			fmt.Printf("--into /%d must be longer than the prefix of %s\n", prefix, network)
+			os.Exit(1)
+		}
+
+		err = enumerateSubnets(network, prefix, page{limit: limit}, func(i uint64, sub block) bool {
+			fmt.Printf("%s\n", sub)
+			return true
+		})
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(deaggregateCmd)
+
+	deaggregateCmd.Flags().String("into", "", "the prefix length to split the network into, e.g. /24")
+	deaggregateCmd.Flags().Uint64("limit", 0, "print at most this many blocks (0 for all)")
+	deaggregateCmd.MarkFlagRequired("into")
+}
@@ -0,0 +1,36 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestDeaggregate(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"deaggregate", "--into", "/24", "10.0.0.0/22"},
+			"10.0.0.0/24\n10.0.1.0/24\n10.0.2.0/24\n10.0.3.0/24\n"},
+		{[]string{"deaggregate", "--into", "25", "10.0.0.0/24"},
+			"10.0.0.0/25\n10.0.0.128/25\n"},
+		{[]string{"deaggregate", "--into", "/24", "--limit", "2", "10.0.0.0/16"},
+			"10.0.0.0/24\n10.0.1.0/24\n"},
+	}
+
+	for _, tt := range tests {
+		if got := runRoot(t, tt.args...); got != tt.want {
+			t.Errorf("cidr %v printed %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
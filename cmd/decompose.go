@@ -0,0 +1,215 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// decomposeCmd represents the decompose command
+var decomposeCmd = &cobra.Command{
+	Use:     "unpack <address>",
+	Aliases: []string{"decompose"},
+	Short:   "unpack an address into the value of each mask field",
+	Long: `Reverse the packing done by pack (and the bare command): the
+within's bits are cleared from the address and what remains is split
+into the mask's fields.  unpack takes the same flags as pack, so any
+pack command line can be reversed by changing its verb and giving the
+result as the argument.  decompose is an alias of unpack.  Example:
+
+	cidr unpack --mask 12:region,8:pod,6:rack,6:host --within 172.16.0.0 172.16.16.65
+
+returns
+
+	region=0 pod=1 rack=1 host=1
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		mask, err := maskFromFlags(cmd)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		within, err := cmd.Flags().GetString("within")
+		if err != nil {
+			panic(err)
+		}
+		opts, err := translateOptionsFromFlags(cmd, within)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		// an auto family follows the address being unpacked
+		family, err := cmd.Flags().GetString("family")
+		if err != nil {
+			panic(err)
+		}
+		if family == "auto" && strings.Contains(args[0], ":") {
+			opts.family = 6
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+		sep, err := cmd.Flags().GetString("field-separator-output")
+		if err != nil {
+			panic(err)
+		}
+
+		result, err := decompose(args[0], mask, within, opts)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		if err := writeFieldValues(os.Stdout, result, output, unescapeSeparator(sep)); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// a single field of a decomposed address
+type fieldValue struct {
+	Name  string `json:"name" yaml:"name"`
+	Width int    `json:"width" yaml:"width"`
+	Value int    `json:"value" yaml:"value"`
+}
+
+// unpack the address into the mask's fields after clearing the within,
+// reversing pack with the same options
+func decompose(address, mask, within string, opts translateOptions) ([]fieldValue, error) {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is not a valid address", address)
+	}
+	if addr.BitLen() != opts.addrBits() {
+		return nil, fmt.Errorf("'%s' is not an IPv%d address", address, opts.family)
+	}
+
+	bits := opts.addrBits()
+	if opts.lenient {
+		bits = 0
+	}
+	fields, names, err := parseNamedMaskBits(mask, bits, opts.widthUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	// clear the within's bits, and every bit of an explicit prefix
+	within, fixed, err := splitWithin(within, opts.addrBits())
+	if err != nil {
+		return nil, err
+	}
+	var w []byte
+	if opts.family == 6 {
+		a, err := parseWithin6(within)
+		if err != nil {
+			return nil, err
+		}
+		w = a.AsSlice()
+	} else {
+		octets, err := parseWithin(within, opts.withinMask, nil)
+		if err != nil {
+			return nil, err
+		}
+		w = ipv4ToAddr(octetsToIPv4(octets)).AsSlice()
+	}
+	b := addr.AsSlice()
+	for i := range b {
+		b[i] &^= w[i]
+	}
+	n := new(big.Int).SetBytes(b)
+	if fixed > 0 {
+		n.And(n, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(opts.addrBits()-fixed)), big.NewInt(1)))
+	}
+
+	// the last field is in the least significant bits, unless lsbFirst
+	values := make([]int, len(fields))
+	for k := range fields {
+		i := len(fields) - k - 1
+		if opts.lsbFirst {
+			i = k
+		}
+		mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(fields[i])), big.NewInt(1))
+		values[i] = int(new(big.Int).And(n, mask).Int64())
+		n.Rsh(n, uint(fields[i]))
+	}
+
+	result := make([]fieldValue, len(fields))
+	for i, f := range fields {
+		result[i] = fieldValue{Name: fieldKey(names, i), Width: f, Value: values[i]}
+	}
+	return result, nil
+}
+
+// split a 32 bit value into fields, most significant first.
+// this is the inverse of computeCIDR.
+func unpackFields(addr uint32, fields []int) []int {
+	values := make([]int, len(fields))
+	for i := len(fields) - 1; i >= 0; i-- {
+		values[i] = int(addr & generateAndMask(fields[i]))
+		addr >>= uint32(fields[i])
+	}
+	return values
+}
+
+// the key used for a field in name=value output
+func fieldKey(names []string, i int) string {
+	if i < len(names) && len(names[i]) > 0 {
+		return names[i]
+	}
+	return fmt.Sprintf("field%d", i)
+}
+
+// expand the \n and \t escapes of a separator given on the command line
+func unescapeSeparator(sep string) string {
+	return strings.NewReplacer(`\n`, "\n", `\t`, "\t").Replace(sep)
+}
+
+// write the fields as name=value pairs joined by sep (bare values with
+// --result-only), or as a list
+func writeFieldValues(w io.Writer, values []fieldValue, output, sep string) error {
+	if output == "text" {
+		pairs := make([]string, len(values))
+		for i, v := range values {
+			pairs[i] = fmt.Sprintf("%s=%d", v.Name, v.Value)
+			if resultOnly {
+				pairs[i] = fmt.Sprintf("%d", v.Value)
+			}
+		}
+		fmt.Fprintf(w, "%s\n", strings.Join(pairs, sep))
+		return nil
+	}
+
+	return writeEncoded(w, values, output)
+}
+
+func init() {
+	RootCmd.AddCommand(decomposeCmd)
+
+	decomposeCmd.Flags().String("field-separator-output", " ", `join the name=value pairs of text output with this, e.g. "," or "\n"`)
+}
@@ -0,0 +1,62 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecompose(t *testing.T) {
+	values, err := decompose("172.16.16.65", "12:region,8:pod,6:rack,6:host", "172.16.0.0", translateOptions{family: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []fieldValue{{"region", 12, 0}, {"pod", 8, 1}, {"rack", 6, 1}, {"host", 6, 1}}
+	if len(values) != len(want) {
+		t.Fatalf("decompose = %v, want %v", values, want)
+	}
+	for i := range want {
+		if values[i] != want[i] {
+			t.Errorf("field %d = %v, want %v", i, values[i], want[i])
+		}
+	}
+}
+
+func TestFieldSeparatorOutput(t *testing.T) {
+	values := []fieldValue{{"region", 12, 0}, {"pod", 8, 1}, {"host", 12, 65}}
+	tests := []struct {
+		sep, want string
+	}{
+		{" ", "region=0 pod=1 host=65\n"},
+		{",", "region=0,pod=1,host=65\n"},
+		{`\n`, "region=0\npod=1\nhost=65\n"},
+		{`\t`, "region=0\tpod=1\thost=65\n"},
+	}
+
+	for _, tt := range tests {
+		var out strings.Builder
+		if err := writeFieldValues(&out, values, "text", unescapeSeparator(tt.sep)); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != tt.want {
+			t.Errorf("separator %q wrote %q, want %q", tt.sep, out.String(), tt.want)
+		}
+	}
+
+	got := runRoot(t, "unpack", "--mask", "12.8.6.6", "--within", "172.16.0.0", "--field-separator-output", ",", "172.16.16.65")
+	if got != "field0=0,field1=1,field2=1,field3=1\n" {
+		t.Errorf("unpack --field-separator-output , printed %q", got)
+	}
+}
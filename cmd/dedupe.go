@@ -0,0 +1,129 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// dedupeCmd represents the dedupe command
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe [cidr...]",
+	Short: "remove duplicate and nested CIDRs from a list",
+	Long: `Remove exact duplicates and nested CIDRs from a list, without merging
+any blocks.  With --keep least-specific (the default) a block inside
+another is dropped; with --keep most-specific a block containing another
+is dropped instead.  The CIDRs are read from stdin, one per line, when
+none are given, and are printed sorted.  Example:
+
+	cidr dedupe 10.0.0.0/24 10.0.0.0/25 10.0.0.0/24 10.0.1.0/24
+
+returns
+
+	10.0.0.0/24
+	10.0.1.0/24
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		keep, err := cmd.Flags().GetString("keep")
+		if err != nil {
+			panic(err)
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+		if keep != "least-specific" && keep != "most-specific" {
+			fmt.Printf("unknown --keep '%s', expected most-specific or least-specific\n", keep)
+			os.Exit(1)
+		}
+
+		var blocks []block
+		if len(args) > 0 {
+			for _, arg := range args {
+				b, err := parseBlock(arg)
+				if err != nil {
+					fmt.Printf("%s\n", err)
+					os.Exit(1)
+				}
+				blocks = append(blocks, b)
+			}
+		} else {
+			words, err := splitFlag(cmd)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+			blocks, err = readBlocks(os.Stdin, words)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if err := writeBlocks(os.Stdout, dedupe(blocks, keep == "most-specific"), output); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// sort the blocks and drop duplicates, and either the blocks nested in
+// another or, with mostSpecific, the blocks which contain another
+func dedupe(blocks []block, mostSpecific bool) []block {
+	sorted := make([]block, len(blocks))
+	copy(sorted, blocks)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].base != sorted[j].base {
+			return sorted[i].base < sorted[j].base
+		}
+		return sorted[i].prefix < sorted[j].prefix
+	})
+
+	// blocks are either nested or disjoint, so once sorted a block
+	// containing others is directly followed by one of them
+	var result []block
+	for i, b := range sorted {
+		if i > 0 && sorted[i-1] == b {
+			continue
+		}
+
+		if mostSpecific {
+			j := i + 1
+			for j < len(sorted) && sorted[j] == b {
+				j++
+			}
+			if j < len(sorted) && b.contains(sorted[j]) {
+				continue
+			}
+		} else if len(result) > 0 && result[len(result)-1].contains(b) {
+			continue
+		}
+
+		result = append(result, b)
+	}
+	return result
+}
+
+func init() {
+	RootCmd.AddCommand(dedupeCmd)
+
+	dedupeCmd.Flags().String("keep", "least-specific", "which of two nested blocks to keep: least-specific or most-specific")
+	dedupeCmd.Flags().StringP("output", "o", "text", "output format: text, json or yaml")
+	addSplitFlag(dedupeCmd)
+}
@@ -0,0 +1,41 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestDedupe(t *testing.T) {
+	tests := []struct {
+		cidrs       []string
+		least, most string
+	}{
+		{[]string{"10.0.0.0/24", "10.0.0.0/24"}, "10.0.0.0/24", "10.0.0.0/24"},
+		{[]string{"10.0.0.0/16", "10.0.5.0/24", "10.0.6.0/24"}, "10.0.0.0/16", "10.0.5.0/24 10.0.6.0/24"},
+		{[]string{"10.0.5.0/24", "10.0.0.0/16", "10.0.5.0/24"}, "10.0.0.0/16", "10.0.5.0/24"},
+		{[]string{"10.0.0.0/8", "10.1.0.0/16", "10.1.1.0/24", "192.168.0.0/24"},
+			"10.0.0.0/8 192.168.0.0/24", "10.1.1.0/24 192.168.0.0/24"},
+		// adjacent blocks are neither duplicates nor nested
+		{[]string{"10.0.1.0/24", "10.0.0.0/24"}, "10.0.0.0/24 10.0.1.0/24", "10.0.0.0/24 10.0.1.0/24"},
+	}
+
+	for _, tt := range tests {
+		blocks := parseBlocks(t, tt.cidrs...)
+		if got := blockStrings(dedupe(blocks, false)); got != tt.least {
+			t.Errorf("least specific of %v = %s, want %s", tt.cidrs, got, tt.least)
+		}
+		if got := blockStrings(dedupe(blocks, true)); got != tt.most {
+			t.Errorf("most specific of %v = %s, want %s", tt.cidrs, got, tt.most)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// describeBitCmd represents the describe-bit command
+var describeBitCmd = &cobra.Command{
+	Use:   "describe-bit <n>",
+	Short: "report which field and octet a bit of the address belongs to",
+	Long: `Report which of the mask's fields bit n of the address belongs to,
+its position within that field and the value it is worth there, and its
+octet.  Bits are numbered 0-31 from the most significant, as are the
+bits of each field and octet.  Example:
+
+	cidr describe-bit --mask 12.8.6.6 15
+
+returns
+
+	field #1 (8 bits), bit 3 (worth 16)
+	octet 1, bit 7
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		mask, err := cmd.Flags().GetString("mask")
+		if err != nil {
+			panic(err)
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 0 || n > 31 {
+			fmt.Printf("'%s' is not a valid bit, expected 0-31\n", args[0])
+			os.Exit(1)
+		}
+
+		pos, err := describeBit(mask, n)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		if output == "text" && resultOnly {
+			fmt.Printf("%d %d %d %d\n", pos.Field, pos.FieldBit, pos.Octet, pos.OctetBit)
+			return
+		}
+		if output == "text" {
+			fmt.Printf("%s (%d bits), bit %d (worth %d)\n", pos.label, pos.FieldWidth, pos.FieldBit, pos.Worth)
+			fmt.Printf("octet %d, bit %d\n", pos.Octet, pos.OctetBit)
+			return
+		}
+		if err := writeEncoded(os.Stdout, pos, output); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// where a bit of the address lies in the mask's fields and the octets
+type bitPosition struct {
+	Bit        int    `json:"bit" yaml:"bit"`
+	Field      int    `json:"field" yaml:"field"`
+	FieldName  string `json:"field_name,omitempty" yaml:"field_name,omitempty"`
+	FieldWidth int    `json:"field_width" yaml:"field_width"`
+	FieldBit   int    `json:"field_bit" yaml:"field_bit"`
+	Worth      uint32 `json:"worth" yaml:"worth"`
+	Octet      int    `json:"octet" yaml:"octet"`
+	OctetBit   int    `json:"octet_bit" yaml:"octet_bit"`
+
+	label string
+}
+
+// locate bit n, counted from the most significant, in the mask's fields
+func describeBit(mask string, n int) (bitPosition, error) {
+	fields, names, err := parseNamedMask(mask)
+	if err != nil {
+		return bitPosition{}, err
+	}
+
+	start := 0
+	for i, f := range fields {
+		if n >= start+f {
+			start += f
+			continue
+		}
+		return bitPosition{
+			Bit:        n,
+			Field:      i,
+			FieldName:  names[i],
+			FieldWidth: f,
+			FieldBit:   n - start,
+			Worth:      uint32(1) << uint(f-1-(n-start)),
+			Octet:      n / 8,
+			OctetBit:   n % 8,
+			label:      fieldLabel(names, i),
+		}, nil
+	}
+	return bitPosition{}, fmt.Errorf("bit %d is beyond the mask's %d bits", n, start)
+}
+
+func init() {
+	RootCmd.AddCommand(describeBitCmd)
+
+	describeBitCmd.Flags().StringP("mask", "m", "8:13:4:7", "bitmask whose fields to locate the bit in")
+	describeBitCmd.Flags().StringP("output", "o", "text", "output format: text, json or yaml")
+}
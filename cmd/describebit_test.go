@@ -0,0 +1,70 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDescribeBit(t *testing.T) {
+	tests := []struct {
+		bit  int
+		want bitPosition
+	}{
+		{0, bitPosition{Bit: 0, Field: 0, FieldWidth: 12, FieldBit: 0, Worth: 2048, Octet: 0, OctetBit: 0}},
+		{7, bitPosition{Bit: 7, Field: 0, FieldWidth: 12, FieldBit: 7, Worth: 16, Octet: 0, OctetBit: 7}},
+		{11, bitPosition{Bit: 11, Field: 0, FieldWidth: 12, FieldBit: 11, Worth: 1, Octet: 1, OctetBit: 3}},
+		{12, bitPosition{Bit: 12, Field: 1, FieldWidth: 8, FieldBit: 0, Worth: 128, Octet: 1, OctetBit: 4}},
+		{15, bitPosition{Bit: 15, Field: 1, FieldWidth: 8, FieldBit: 3, Worth: 16, Octet: 1, OctetBit: 7}},
+		{20, bitPosition{Bit: 20, Field: 2, FieldWidth: 6, FieldBit: 0, Worth: 32, Octet: 2, OctetBit: 4}},
+		{31, bitPosition{Bit: 31, Field: 3, FieldWidth: 6, FieldBit: 5, Worth: 1, Octet: 3, OctetBit: 7}},
+	}
+
+	for _, tt := range tests {
+		got, err := describeBit("12.8.6.6", tt.bit)
+		if err != nil {
+			t.Errorf("describeBit(%d): %s", tt.bit, err)
+			continue
+		}
+		got.label = ""
+		if got != tt.want {
+			t.Errorf("describeBit(%d) = %+v, want %+v", tt.bit, got, tt.want)
+		}
+	}
+}
+
+func TestDescribeBitNamed(t *testing.T) {
+	got, err := describeBit("12:region,8:pod,6:rack,6:host", 26)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.FieldName != "host" || got.label != "host" || got.FieldBit != 0 || got.Worth != 32 {
+		t.Errorf("describeBit(26) = %+v, want bit 0 of host", got)
+	}
+
+	if _, err := describeBit("16.16", 32); err == nil || !strings.Contains(err.Error(), "beyond the mask's 32 bits") {
+		t.Errorf("describeBit past the mask: %v", err)
+	}
+}
+
+func TestDescribeBitCommand(t *testing.T) {
+	want := "field #1 (8 bits), bit 3 (worth 16)\noctet 1, bit 7\n"
+	if got := runRoot(t, "describe-bit", "--mask", "12.8.6.6", "15"); got != want {
+		t.Errorf("describe-bit 15 printed %q, want %q", got, want)
+	}
+	if got := runRoot(t, "--result-only", "describe-bit", "--mask", "12.8.6.6", "15"); got != "1 3 1 7\n" {
+		t.Errorf("describe-bit --result-only 15 printed %q", got)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// encodeURLCmd represents the encode-url command
+var encodeURLCmd = &cobra.Command{
+	Use:   "encode-url <value>",
+	Short: "encode a calculation as a shareable URL",
+	Long: `Encode the value, --mask and --within of a calculation into a URL
+query string, appended to --base-url when one is given, so it can be
+shared and reproduced with decode-url.  Example:
+
+	cidr encode-url --mask 12:8:4:8 --within 172.16.0.0 1.1.1.1 --base-url https://cidr.example.com/
+
+returns
+
+	https://cidr.example.com/?mask=12%3A8%3A4%3A8&value=1.1.1.1&within=172.16.0.0
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		mask, err := cmd.Flags().GetString("mask")
+		if err != nil {
+			panic(err)
+		}
+		within, err := cmd.Flags().GetString("within")
+		if err != nil {
+			panic(err)
+		}
+		base, err := cmd.Flags().GetString("base-url")
+		if err != nil {
+			panic(err)
+		}
+
+		link, err := encodeCalculation(base, args[0], mask, within)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s\n", link)
+	},
+}
+
+// decodeURLCmd represents the decode-url command
+var decodeURLCmd = &cobra.Command{
+	Use:   "decode-url <url>",
+	Short: "translate a calculation shared by encode-url",
+	Long: `Read the value, mask and within from a URL made by encode-url
+(or from its bare query string) and translate them.  Example:
+
+	cidr decode-url 'https://cidr.example.com/?mask=12%3A8%3A4%3A8&value=1.1.1.1&within=172.16.0.0'
+
+returns
+
+	172.16.17.1
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		value, mask, within, err := decodeCalculation(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		family, err := parseFamily("auto", within)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		str, err := translate(value, mask, within, translateOptions{family: family})
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s\n", str)
+	},
+}
+
+// encode the calculation as a query string, appended to base if given
+func encodeCalculation(base, value, mask, within string) (string, error) {
+	q := url.Values{}
+	q.Set("value", value)
+	q.Set("mask", mask)
+	q.Set("within", within)
+
+	if len(base) == 0 {
+		return q.Encode(), nil
+	}
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("'%s' is not a valid URL -- %s", base, err)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// decode a URL, or a bare query string, made by encodeCalculation
+func decodeCalculation(link string) (value, mask, within string, err error) {
+	query := link
+	if i := strings.IndexByte(link, '?'); i >= 0 {
+		query = link[i+1:]
+	}
+	if i := strings.IndexByte(query, '#'); i >= 0 {
+		query = query[:i]
+	}
+
+	q, err := url.ParseQuery(query)
+	if err != nil {
+		return "", "", "", fmt.Errorf("'%s' has an invalid query string -- %s", link, err)
+	}
+
+	for _, key := range []string{"value", "mask", "within"} {
+		if len(q[key]) != 1 {
+			return "", "", "", fmt.Errorf("'%s' must give exactly one %s", link, key)
+		}
+	}
+	return q.Get("value"), q.Get("mask"), q.Get("within"), nil
+}
+
+func init() {
+	RootCmd.AddCommand(encodeURLCmd)
+	RootCmd.AddCommand(decodeURLCmd)
+
+	encodeURLCmd.Flags().StringP("mask", "m", "8:13:4:7", "bitmask for translation")
+	encodeURLCmd.Flags().StringP("within", "w", "0.0.0.0", "result is OR'ed with this CIDR")
+	encodeURLCmd.Flags().String("base-url", "", "the URL to append the query string to; without it only the query string is printed")
+}
@@ -0,0 +1,81 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCalculationRoundTrip(t *testing.T) {
+	tests := []struct {
+		base, value, mask, within string
+	}{
+		{"", "1.1.1.1", "12:8:4:8", "172.16.0.0"},
+		{"https://cidr.example.com/", "1.1.1.1", "12:8:4:8", "172.16.0.0"},
+		{"https://cidr.example.com/calc?old=1#top", "0.1.1.1", "12:region,8:pod,6:rack,6:host", "172.16.0.0/12"},
+		{"", "0.1.1", "3*8+6+2", "10.0.0.0"},
+		{"", "0.1.1.1", "16.16.16.16.16.16.16", "2001:db8::"},
+		{"https://cidr.example.com/", "1 2", "8:pod[0-200],24 # & hash", "0.0.0.0"},
+	}
+
+	for _, tt := range tests {
+		link, err := encodeCalculation(tt.base, tt.value, tt.mask, tt.within)
+		if err != nil {
+			t.Errorf("encodeCalculation(%q, %q, %q, %q): %s", tt.base, tt.value, tt.mask, tt.within, err)
+			continue
+		}
+		value, mask, within, err := decodeCalculation(link)
+		if err != nil {
+			t.Errorf("decodeCalculation(%q): %s", link, err)
+			continue
+		}
+		if value != tt.value || mask != tt.mask || within != tt.within {
+			t.Errorf("%q decoded to %q, %q, %q, want %q, %q, %q", link, value, mask, within, tt.value, tt.mask, tt.within)
+		}
+	}
+}
+
+func TestEncodeURL(t *testing.T) {
+	want := "https://cidr.example.com/?mask=12%3A8%3A4%3A8&value=1.1.1.1&within=172.16.0.0\n"
+	got := runRoot(t, "encode-url", "--mask", "12:8:4:8", "--within", "172.16.0.0", "1.1.1.1", "--base-url", "https://cidr.example.com/")
+	if got != want {
+		t.Errorf("encode-url printed %q, want %q", got, want)
+	}
+
+	if got := runRoot(t, "decode-url", strings.TrimSuffix(want, "\n")); got != "172.16.17.1\n" {
+		t.Errorf("decode-url printed %q, want 172.16.17.1", got)
+	}
+	if got := runRoot(t, "decode-url", "mask=16.8.8&value=0.1.1&within=10.0.0.0"); got != "10.0.1.1\n" {
+		t.Errorf("decode-url of a bare query printed %q, want 10.0.1.1", got)
+	}
+}
+
+func TestDecodeCalculationErrors(t *testing.T) {
+	tests := []struct {
+		link, want string
+	}{
+		{"https://cidr.example.com/?mask=8.8.8.8&within=0.0.0.0", "exactly one value"},
+		{"value=1.1.1.1&value=2.2.2.2&mask=8.8.8.8&within=0.0.0.0", "exactly one value"},
+		{"value=1.1.1.1&mask=8.8.8.8", "exactly one within"},
+		{"value=%zz&mask=8.8.8.8&within=0.0.0.0", "invalid query string"},
+	}
+
+	for _, tt := range tests {
+		_, _, _, err := decodeCalculation(tt.link)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("decodeCalculation(%q) = %v, want %q", tt.link, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// parse a prefix length given as 24 or /24
+func parsePrefixLen(s string) (int, error) {
+	prefix, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(s), "/"))
+	if err != nil || prefix < 0 || prefix > 32 {
+		return 0, fmt.Errorf("'%s' is not a valid prefix length, expected 0-32", s)
+	}
+	return prefix, nil
+}
+
+// the number of prefix sized subnets in the block
+func subnetCount(b block, prefix int) (uint64, error) {
+	if prefix < b.prefix || prefix > 32 {
+		return 0, fmt.Errorf("/%d does not fit within %s", prefix, b)
+	}
+	return uint64(1) << uint(prefix-b.prefix), nil
+}
+
+// a window onto an enumeration
+type page struct {
+	start uint64 // the number of entries to skip
+	limit uint64 // the most entries to visit, 0 for no limit
+	desc  bool   // visit the entries from the last to the first
+}
+
+// the number of entries in the window, for an enumeration of count entries
+func (p page) size(count uint64) uint64 {
+	if p.start >= count {
+		return 0
+	}
+	n := count - p.start
+	if p.limit > 0 && p.limit < n {
+		n = p.limit
+	}
+	return n
+}
+
+// call fn with the index of each entry in the window, for an enumeration
+// of count entries.  fn may return false to stop early.
+func (p page) each(count uint64, fn func(i uint64) bool) {
+	// size clamps the limit to what remains, so this can't overflow
+	end := p.start + p.size(count)
+
+	for k := p.start; k < end; k++ {
+		i := k
+		if p.desc {
+			i = count - 1 - k
+		}
+		if !fn(i) {
+			break
+		}
+	}
+}
+
+// call fn with each prefix sized subnet of the block in the page
+func enumerateSubnets(b block, prefix int, pg page, fn func(i uint64, sub block) bool) error {
+	count, err := subnetCount(b, prefix)
+	if err != nil {
+		return err
+	}
+
+	step := uint64(1) << uint(32-prefix)
+	pg.each(count, func(i uint64) bool {
+		return fn(i, block{base: uint32(uint64(b.base) + i*step), prefix: prefix})
+	})
+	return nil
+}
+
+// call fn with each address of the block in the page
+func enumerateHosts(b block, pg page, fn func(i uint64, addr uint32) bool) {
+	if pg.desc {
+		pg.each(b.size(), func(i uint64) bool {
+			return fn(i, uint32(uint64(b.base)+i))
+		})
+		return
+	}
+
+	if pg.start >= b.size() {
+		return
+	}
+	i := pg.start
+	for addr := range iterateFrom(ipv4ToAddr(uint32(uint64(b.base)+i)), b.netipPrefix()) {
+		if pg.limit > 0 && i-pg.start >= pg.limit {
+			break
+		}
+		if !fn(i, addrToIPv4(addr)) {
+			break
+		}
+		i++
+	}
+}
+
+// a subnet of an enumeration, as one line of NDJSON output
+type subnetEntry struct {
+	Index uint64 `json:"index"`
+	CIDR  string `json:"cidr"`
+	First string `json:"first"`
+	Last  string `json:"last"`
+}
+
+// an address of an enumeration, as one line of NDJSON output
+type hostEntry struct {
+	Index   uint64 `json:"index"`
+	Address string `json:"address"`
+}
+
+// write each subnet in the page as a JSON object on its own line, as it
+// is enumerated, so memory stays flat however many there are
+func writeSubnetsNDJSON(w io.Writer, b block, prefix int, pg page) error {
+	enc := json.NewEncoder(w)
+
+	var werr error
+	err := enumerateSubnets(b, prefix, pg, func(i uint64, sub block) bool {
+		werr = enc.Encode(subnetEntry{
+			Index: i,
+			CIDR:  sub.String(),
+			First: formatIPv4(sub.base),
+			Last:  formatIPv4(sub.last()),
+		})
+		return werr == nil
+	})
+	if err != nil {
+		return err
+	}
+	return werr
+}
+
+// write each address in the page as a JSON object on its own line
+func writeHostsNDJSON(w io.Writer, b block, pg page) error {
+	enc := json.NewEncoder(w)
+
+	var werr error
+	enumerateHosts(b, pg, func(i uint64, addr uint32) bool {
+		werr = enc.Encode(hostEntry{Index: i, Address: formatIPv4(addr)})
+		return werr == nil
+	})
+	return werr
+}
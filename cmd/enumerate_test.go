@@ -0,0 +1,225 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// the subnets and hosts a page of the network visits
+func window(t *testing.T, network string, prefix int, pg page) ([]string, []string) {
+	t.Helper()
+
+	b, err := parseBlock(network)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var subnets, hosts []string
+	err = enumerateSubnets(b, prefix, pg, func(i uint64, sub block) bool {
+		subnets = append(subnets, sub.String())
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	enumerateHosts(b, pg, func(i uint64, addr uint32) bool {
+		hosts = append(hosts, formatIPv4(addr))
+		return true
+	})
+	return subnets, hosts
+}
+
+func TestPageWindows(t *testing.T) {
+	tests := []struct {
+		pg      page
+		subnets []string
+		hosts   []string
+	}{
+		{page{}, []string{"10.0.0.0/30", "10.0.0.4/30", "10.0.0.8/30", "10.0.0.12/30"},
+			[]string{"10.0.0.0", "10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4", "10.0.0.5", "10.0.0.6", "10.0.0.7",
+				"10.0.0.8", "10.0.0.9", "10.0.0.10", "10.0.0.11", "10.0.0.12", "10.0.0.13", "10.0.0.14", "10.0.0.15"}},
+		{page{start: 1, limit: 2}, []string{"10.0.0.4/30", "10.0.0.8/30"},
+			[]string{"10.0.0.1", "10.0.0.2"}},
+		{page{start: 3}, []string{"10.0.0.12/30"},
+			[]string{"10.0.0.3", "10.0.0.4", "10.0.0.5", "10.0.0.6", "10.0.0.7", "10.0.0.8", "10.0.0.9",
+				"10.0.0.10", "10.0.0.11", "10.0.0.12", "10.0.0.13", "10.0.0.14", "10.0.0.15"}},
+		{page{start: 14, limit: 10}, nil, []string{"10.0.0.14", "10.0.0.15"}},
+		{page{start: 16}, nil, nil},
+		// a limit which overflows when added to the start
+		{page{start: 14, limit: math.MaxUint64}, nil, []string{"10.0.0.14", "10.0.0.15"}},
+		{page{start: 3, limit: math.MaxUint64 - 1, desc: true}, []string{"10.0.0.0/30"},
+			[]string{"10.0.0.12", "10.0.0.11", "10.0.0.10", "10.0.0.9", "10.0.0.8", "10.0.0.7", "10.0.0.6",
+				"10.0.0.5", "10.0.0.4", "10.0.0.3", "10.0.0.2", "10.0.0.1", "10.0.0.0"}},
+		{page{limit: 1, desc: true}, []string{"10.0.0.12/30"}, []string{"10.0.0.15"}},
+		{page{start: 1, limit: 2, desc: true}, []string{"10.0.0.8/30", "10.0.0.4/30"},
+			[]string{"10.0.0.14", "10.0.0.13"}},
+	}
+
+	for _, tt := range tests {
+		subnets, hosts := window(t, "10.0.0.0/28", 30, tt.pg)
+		if !slices.Equal(subnets, tt.subnets) {
+			t.Errorf("page %+v visits the subnets %v, want %v", tt.pg, subnets, tt.subnets)
+		}
+		if !slices.Equal(hosts, tt.hosts) {
+			t.Errorf("page %+v visits the hosts %v, want %v", tt.pg, hosts, tt.hosts)
+		}
+	}
+}
+
+// consecutive pages cover the network exactly once
+func TestPagesTile(t *testing.T) {
+	all, _ := window(t, "10.0.0.0/24", 28, page{})
+
+	var paged []string
+	for start := uint64(0); start < 16; start += 5 {
+		subnets, _ := window(t, "10.0.0.0/24", 28, page{start: start, limit: 5})
+		paged = append(paged, subnets...)
+	}
+	if !slices.Equal(paged, all) {
+		t.Errorf("pages of 5 visit %v, want %v", paged, all)
+	}
+}
+
+func TestSortDirections(t *testing.T) {
+	ascSubnets, ascHosts := window(t, "10.0.0.0/29", 31, page{})
+	descSubnets, descHosts := window(t, "10.0.0.0/29", 31, page{desc: true})
+
+	if want := []string{"10.0.0.0/31", "10.0.0.2/31", "10.0.0.4/31", "10.0.0.6/31"}; !slices.Equal(ascSubnets, want) {
+		t.Errorf("ascending subnets %v, want %v", ascSubnets, want)
+	}
+	if want := []string{"10.0.0.6/31", "10.0.0.4/31", "10.0.0.2/31", "10.0.0.0/31"}; !slices.Equal(descSubnets, want) {
+		t.Errorf("descending subnets %v, want %v", descSubnets, want)
+	}
+
+	slices.Reverse(descHosts)
+	if !slices.Equal(ascHosts, descHosts) || len(ascHosts) != 8 {
+		t.Errorf("descending hosts %v are not the reverse of %v", descHosts, ascHosts)
+	}
+}
+
+// decode each line on its own, failing on unknown fields or trailing data
+func decodeLines[T any](t *testing.T, out string) []T {
+	t.Helper()
+
+	var entries []T
+	for i, line := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		dec := json.NewDecoder(strings.NewReader(line))
+		dec.DisallowUnknownFields()
+		var e T
+		if err := dec.Decode(&e); err != nil {
+			t.Fatalf("line %d %q is not valid JSON: %s", i+1, line, err)
+		}
+		if dec.More() {
+			t.Fatalf("line %d %q holds more than one object", i+1, line)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestSubnetsNDJSON(t *testing.T) {
+	var out strings.Builder
+	if err := writeSubnetsNDJSON(&out, parseBlocks(t, "10.0.0.0/22")[0], 24, page{}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := decodeLines[subnetEntry](t, out.String())
+	want := []subnetEntry{
+		{0, "10.0.0.0/24", "10.0.0.0", "10.0.0.255"},
+		{1, "10.0.1.0/24", "10.0.1.0", "10.0.1.255"},
+		{2, "10.0.2.0/24", "10.0.2.0", "10.0.2.255"},
+		{3, "10.0.3.0/24", "10.0.3.0", "10.0.3.255"},
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d subnets, want %d", len(entries), len(want))
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("line %d = %v, want %v", i+1, entries[i], want[i])
+		}
+	}
+}
+
+func TestHostsNDJSON(t *testing.T) {
+	var out strings.Builder
+	if err := writeHostsNDJSON(&out, parseBlocks(t, "192.168.1.0/24")[0], page{start: 10, limit: 3}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := decodeLines[hostEntry](t, out.String())
+	want := []hostEntry{{10, "192.168.1.10"}, {11, "192.168.1.11"}, {12, "192.168.1.12"}}
+	if len(entries) != len(want) {
+		t.Fatalf("got %d hosts, want %d", len(entries), len(want))
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("line %d = %v, want %v", i+1, entries[i], want[i])
+		}
+	}
+}
+
+// accepts a few lines, then fails like a closed pipe
+type failingWriter struct {
+	bytes.Buffer
+	lines int
+}
+
+var errClosed = errors.New("closed")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.lines == 0 {
+		return 0, errClosed
+	}
+	w.lines--
+	return w.Buffer.Write(p)
+}
+
+func TestNDJSONStreams(t *testing.T) {
+	// each line is written as it is enumerated, so a failed write stops
+	// a /8's 16 million hosts at once
+	w := &failingWriter{lines: 3}
+	if err := writeHostsNDJSON(w, parseBlocks(t, "10.0.0.0/8")[0], page{}); !errors.Is(err, errClosed) {
+		t.Fatalf("writeHostsNDJSON = %v, want the write error", err)
+	}
+	if entries := decodeLines[hostEntry](t, w.String()); len(entries) != 3 {
+		t.Errorf("%d lines were written before the failure, want 3", len(entries))
+	}
+
+	w = &failingWriter{lines: 2}
+	if err := writeSubnetsNDJSON(w, parseBlocks(t, "10.0.0.0/8")[0], 30, page{}); !errors.Is(err, errClosed) {
+		t.Fatalf("writeSubnetsNDJSON = %v, want the write error", err)
+	}
+}
+
+func TestNDJSONCommands(t *testing.T) {
+	out := runRoot(t, "subnets", "10.0.0.0/23", "1", "--output", "ndjson")
+	if entries := decodeLines[subnetEntry](t, out); len(entries) != 2 || entries[1].CIDR != "10.0.1.0/24" {
+		t.Errorf("subnets --output ndjson printed %q", out)
+	}
+	out = runRoot(t, "equal-split-table", "--within", "10.0.0.0/23", "--subnet", "/24", "--output", "ndjson")
+	if entries := decodeLines[subnetEntry](t, out); len(entries) != 2 || entries[0].Last != "10.0.0.255" {
+		t.Errorf("equal-split-table --output ndjson printed %q", out)
+	}
+	out = runRoot(t, "hosts", "10.0.0.0/30", "--output", "ndjson")
+	if entries := decodeLines[hostEntry](t, out); len(entries) != 4 || entries[3].Address != "10.0.0.3" {
+		t.Errorf("hosts --output ndjson printed %q", out)
+	}
+}
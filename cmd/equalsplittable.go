@@ -0,0 +1,111 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// equalSplitTableCmd represents the equal-split-table command
+var equalSplitTableCmd = &cobra.Command{
+	Use:   "equal-split-table",
+	Short: "print an allocation plan splitting a network into equal subnets",
+	Long: `Split the network into subnets of one size and print them as a
+numbered table, ready to paste into a planning document.  Example:
+
+	cidr equal-split-table --within 10.0.0.0/22 --subnet /24
+
+returns
+
+	INDEX  SUBNET       FIRST     LAST
+	0      10.0.0.0/24  10.0.0.0  10.0.0.255
+	1      10.0.1.0/24  10.0.1.0  10.0.1.255
+	2      10.0.2.0/24  10.0.2.0  10.0.2.255
+	3      10.0.3.0/24  10.0.3.0  10.0.3.255
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		within, err := cmd.Flags().GetString("within")
+		if err != nil {
+			panic(err)
+		}
+		subnet, err := cmd.Flags().GetString("subnet")
+		if err != nil {
+			panic(err)
+		}
+		pg, err := pageFlags(cmd)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		ndjson, err := enumOutputFlag(cmd)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		network, err := parseBlock(within)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		prefix, err := parsePrefixLen(subnet)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		if ndjson {
+			err = writeSubnetsNDJSON(os.Stdout, network, prefix, pg)
+		} else {
+			err = writeSplitTable(os.Stdout, network, prefix, pg)
+		}
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// write the numbered table of subnets
+func writeSplitTable(w io.Writer, network block, prefix int, pg page) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	if !resultOnly {
+		fmt.Fprintf(tw, "INDEX\tSUBNET\tFIRST\tLAST\n")
+	}
+
+	err := enumerateSubnets(network, prefix, pg, func(i uint64, sub block) bool {
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", i, sub, formatIPv4(sub.base), formatIPv4(sub.last()))
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	return tw.Flush()
+}
+
+func init() {
+	RootCmd.AddCommand(equalSplitTableCmd)
+
+	equalSplitTableCmd.Flags().StringP("within", "w", "", "the network to split, in CIDR notation")
+	equalSplitTableCmd.Flags().StringP("subnet", "s", "", "the size of each subnet, e.g. /24")
+	addPageFlags(equalSplitTableCmd)
+	addEnumOutputFlag(equalSplitTableCmd)
+}
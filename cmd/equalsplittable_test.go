@@ -0,0 +1,68 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteSplitTable(t *testing.T) {
+	tests := []struct {
+		network string
+		prefix  int
+		pg      page
+		want    string
+	}{
+		{"10.0.0.0/24", 26, page{}, `INDEX  SUBNET         FIRST       LAST
+0      10.0.0.0/26    10.0.0.0    10.0.0.63
+1      10.0.0.64/26   10.0.0.64   10.0.0.127
+2      10.0.0.128/26  10.0.0.128  10.0.0.191
+3      10.0.0.192/26  10.0.0.192  10.0.0.255
+`},
+		{"10.0.0.0/16", 24, page{start: 254, limit: 5}, `INDEX  SUBNET         FIRST       LAST
+254    10.0.254.0/24  10.0.254.0  10.0.254.255
+255    10.0.255.0/24  10.0.255.0  10.0.255.255
+`},
+		{"10.0.0.0/30", 32, page{start: 1, limit: 2}, `INDEX  SUBNET       FIRST     LAST
+1      10.0.0.1/32  10.0.0.1  10.0.0.1
+2      10.0.0.2/32  10.0.0.2  10.0.0.2
+`},
+	}
+
+	for _, tt := range tests {
+		network, err := parseBlock(tt.network)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sb strings.Builder
+		if err := writeSplitTable(&sb, network, tt.prefix, tt.pg); err != nil {
+			t.Errorf("splitting %s into /%d: %s", tt.network, tt.prefix, err)
+			continue
+		}
+		if sb.String() != tt.want {
+			t.Errorf("splitting %s into /%d gave\n%s\nwant\n%s", tt.network, tt.prefix, sb.String(), tt.want)
+		}
+	}
+}
+
+func TestWriteSplitTableTooSmall(t *testing.T) {
+	network, err := parseBlock("10.0.0.0/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := writeSplitTable(&strings.Builder{}, network, 16, page{}); err == nil {
+		t.Error("expected a /24 to refuse splitting into /16s")
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+)
+
+// the invocations printed by --examples
+var examples = []struct {
+	value, mask, within string
+}{
+	{"0.1.1.1", "12.8.6.6", "172.16.0.0"},
+	{"0.255.63.63", "12.8.6.6", "172.16.0.0"},
+	{"0.2.3.4", "12:region,8:pod,6:rack,6:host", "172.16.0.0"},
+	{"10.1.2.3", "8.8.8.8", "0.0.0.0"},
+	{"0.5.0", "8.16.8", "10.0.0.0"},
+	{"1:2:3:4", "8:13:4:7", "0.0.0.0"},
+}
+
+// print each example with the output of actually running it, so the
+// examples can never go stale
+func printExamples(w io.Writer) {
+	for i, ex := range examples {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "$ cidr --mask %s --within %s %s\n", ex.mask, ex.within, ex.value)
+
+		str, err := translate(ex.value, ex.mask, ex.within, translateOptions{})
+		if err != nil {
+			str = err.Error()
+		}
+		fmt.Fprintf(w, "%s\n", str)
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// fitCmd represents the fit command
+var fitCmd = &cobra.Command{
+	Use:   "fit <count>",
+	Short: "print the smallest IPv4 prefix holding a number of addresses",
+	Long: `Print the longest IPv4 prefix whose networks hold at least count
+addresses.  With --usable the network and broadcast addresses don't
+count, except in a /31 (RFC 3021) or /32.  Example:
+
+	cidr fit 500
+
+returns
+
+	/23
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		usable, err := cmd.Flags().GetBool("usable")
+		if err != nil {
+			panic(err)
+		}
+
+		count, err := strconv.ParseUint(args[0], 10, 64)
+		if err != nil || count == 0 {
+			fmt.Printf("'%s' is not a valid number of addresses\n", args[0])
+			os.Exit(1)
+		}
+
+		prefix, err := fit(count, usable)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("/%d\n", prefix)
+	},
+}
+
+// the number of addresses of a network of the prefix which can be
+// assigned to hosts
+func usableHosts(prefix int) uint64 {
+	switch prefix {
+	case 32:
+		return 1
+	case 31:
+		return 2
+	}
+	return block{prefix: prefix}.size() - 2
+}
+
+// the longest prefix holding count addresses
+func fit(count uint64, usable bool) (int, error) {
+	for prefix := 32; prefix >= 0; prefix-- {
+		capacity := block{prefix: prefix}.size()
+		if usable {
+			capacity = usableHosts(prefix)
+		}
+		if capacity >= count {
+			return prefix, nil
+		}
+	}
+	return 0, fmt.Errorf("%d addresses don't fit in an IPv4 network", count)
+}
+
+func init() {
+	RootCmd.AddCommand(fitCmd)
+
+	fitCmd.Flags().Bool("usable", false, "don't count the network and broadcast addresses")
+}
@@ -0,0 +1,56 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		count          uint64
+		prefix, usable int
+	}{
+		{1, 32, 32},
+		{2, 31, 31},
+		{3, 30, 29},
+		{254, 24, 24},
+		{255, 24, 23},
+		{256, 24, 23},
+		{257, 23, 23},
+		{500, 23, 23},
+		{510, 23, 23},
+		{511, 23, 22},
+		{1 << 32, 0, 0},
+	}
+
+	for _, tt := range tests {
+		got, err := fit(tt.count, false)
+		if err != nil || got != tt.prefix {
+			t.Errorf("fit(%d) = /%d, %v, want /%d", tt.count, got, err, tt.prefix)
+		}
+		if tt.count == 1<<32 {
+			continue
+		}
+		got, err = fit(tt.count, true)
+		if err != nil || got != tt.usable {
+			t.Errorf("fit(%d, usable) = /%d, %v, want /%d", tt.count, got, err, tt.usable)
+		}
+	}
+
+	if got, err := fit(1<<32+1, false); err == nil {
+		t.Errorf("fit(2^32+1) = /%d, expected an error", got)
+	}
+	if got, err := fit(1<<32, true); err == nil {
+		t.Errorf("fit(2^32, usable) = /%d, expected an error", got)
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// fromMACCmd represents the from-mac command
+var fromMACCmd = &cobra.Command{
+	Use:   "from-mac <mac>",
+	Short: "derive an EUI-64 IPv6 address from a MAC address",
+	Long: `Build the modified EUI-64 interface identifier used by SLAAC: ff:fe
+is inserted into the middle of the MAC and the universal/local bit is
+flipped.  The identifier is combined with the /64 (or shorter) prefix.
+Example:
+
+	cidr from-mac --prefix 2001:db8::/64 00:11:22:33:44:55
+
+returns
+
+	2001:db8::211:22ff:fe33:4455
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		prefix, err := cmd.Flags().GetString("prefix")
+		if err != nil {
+			panic(err)
+		}
+
+		addr, err := fromMAC(prefix, args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s\n", addr)
+	},
+}
+
+// combine the prefix with the MAC's modified EUI-64 identifier
+func fromMAC(prefix, mac string) (netip.Addr, error) {
+	p, err := netip.ParsePrefix(prefix)
+	if err != nil || !p.Addr().Is6() {
+		return netip.Addr{}, fmt.Errorf("'%s' is not an IPv6 prefix", prefix)
+	}
+	if p.Bits() > 64 {
+		return netip.Addr{}, fmt.Errorf("'%s' is longer than /64, leaving no room for the interface identifier", prefix)
+	}
+
+	hw, err := net.ParseMAC(mac)
+	if err != nil || len(hw) != 6 {
+		return netip.Addr{}, fmt.Errorf("'%s' is not a 48 bit MAC address", mac)
+	}
+
+	addr := p.Masked().Addr().As16()
+	addr[8] = hw[0] ^ 0x02
+	addr[9] = hw[1]
+	addr[10] = hw[2]
+	addr[11] = 0xff
+	addr[12] = 0xfe
+	addr[13] = hw[3]
+	addr[14] = hw[4]
+	addr[15] = hw[5]
+
+	return netip.AddrFrom16(addr), nil
+}
+
+func init() {
+	RootCmd.AddCommand(fromMACCmd)
+
+	fromMACCmd.Flags().StringP("prefix", "p", "fe80::/64", "the IPv6 prefix to combine with the identifier")
+}
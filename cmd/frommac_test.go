@@ -0,0 +1,61 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestFromMAC(t *testing.T) {
+	tests := []struct {
+		prefix, mac, want string
+	}{
+		{"2001:db8::/64", "00:11:22:33:44:55", "2001:db8::211:22ff:fe33:4455"},
+		{"fe80::/64", "52:74:f2:b1:a8:7f", "fe80::5074:f2ff:feb1:a87f"},
+		// the universal/local bit is flipped either way
+		{"fe80::/64", "02:00:00:00:00:01", "fe80::ff:fe00:1"},
+		{"2001:db8:1:2::/64", "00-1B-63-84-45-E6", "2001:db8:1:2:21b:63ff:fe84:45e6"},
+		// the host bits of the prefix are ignored
+		{"2001:db8::1/64", "00:11:22:33:44:55", "2001:db8::211:22ff:fe33:4455"},
+		{"2001:db8::/48", "00:11:22:33:44:55", "2001:db8::211:22ff:fe33:4455"},
+	}
+
+	for _, tt := range tests {
+		got, err := fromMAC(tt.prefix, tt.mac)
+		if err != nil {
+			t.Errorf("fromMAC(%q, %q): %s", tt.prefix, tt.mac, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("fromMAC(%q, %q) = %s, want %s", tt.prefix, tt.mac, got, tt.want)
+		}
+	}
+}
+
+func TestFromMACErrors(t *testing.T) {
+	tests := []struct {
+		prefix, mac string
+	}{
+		{"2001:db8::/64", "00:11:22:33:44"},
+		{"2001:db8::/64", "00:11:22:33:44:55:66:77"},
+		{"2001:db8::/64", "not a mac"},
+		{"2001:db8::/80", "00:11:22:33:44:55"},
+		{"10.0.0.0/8", "00:11:22:33:44:55"},
+		{"2001:db8::", "00:11:22:33:44:55"},
+	}
+
+	for _, tt := range tests {
+		if got, err := fromMAC(tt.prefix, tt.mac); err == nil {
+			t.Errorf("fromMAC(%q, %q) = %s, expected an error", tt.prefix, tt.mac, got)
+		}
+	}
+}
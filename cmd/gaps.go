@@ -0,0 +1,160 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// gapsCmd represents the gaps command
+var gapsCmd = &cobra.Command{
+	Use:   "gaps --within <supernet> [cidr...]",
+	Short: "report the unallocated gaps between blocks of a supernet",
+	Long: `Report each run of free addresses in the supernet between (and around)
+the allocated blocks, as a range, its size, and the CIDRs covering it.
+Allocations are read from stdin, one per line, when none are given, and
+must lie within the supernet.  Example:
+
+	cidr gaps --within 10.0.0.0/22 10.0.0.0/24 10.0.2.0/25
+
+returns
+
+	10.0.1.0-10.0.1.255 (256 addresses): 10.0.1.0/24
+	10.0.2.128-10.0.3.255 (384 addresses): 10.0.2.128/25 10.0.3.0/24
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		within, err := cmd.Flags().GetString("within")
+		if err != nil {
+			panic(err)
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+
+		supernet, err := parseBlock(within)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		var blocks []block
+		if len(args) > 0 {
+			for _, arg := range args {
+				b, err := parseBlock(arg)
+				if err != nil {
+					fmt.Printf("%s\n", err)
+					os.Exit(1)
+				}
+				blocks = append(blocks, b)
+			}
+		} else {
+			words, err := splitFlag(cmd)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+			blocks, err = readBlocks(os.Stdin, words)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+		}
+
+		result, err := gaps(supernet, blocks)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		if output == "text" {
+			writeGaps(os.Stdout, result)
+			return
+		}
+		if err := writeEncoded(os.Stdout, result, output); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// a run of unallocated addresses
+type gap struct {
+	Start string   `json:"start" yaml:"start"`
+	End   string   `json:"end" yaml:"end"`
+	Size  uint64   `json:"size" yaml:"size"`
+	CIDRs []string `json:"cidrs" yaml:"cidrs"`
+}
+
+// the gaps of the supernet left by the allocations, in order
+func gaps(supernet block, allocations []block) ([]gap, error) {
+	ranges := make([]ipRange, len(allocations))
+	for i, b := range allocations {
+		if !supernet.contains(b) {
+			return nil, fmt.Errorf("%s is not within %s", b, supernet)
+		}
+		ranges[i] = b.span()
+	}
+
+	result := []gap{}
+	add := func(first, last uint32) {
+		r := ipRange{first: first, last: last}
+		g := gap{Start: formatIPv4(first), End: formatIPv4(last), Size: r.size()}
+		for _, b := range r.blocks() {
+			g.CIDRs = append(g.CIDRs, b.String())
+		}
+		result = append(result, g)
+	}
+
+	next := uint64(supernet.base)
+	for _, r := range mergeRanges(ranges) {
+		if uint64(r.first) > next {
+			add(uint32(next), r.first-1)
+		}
+		next = uint64(r.last) + 1
+	}
+	if next <= uint64(supernet.last()) {
+		add(uint32(next), supernet.last())
+	}
+
+	return result, nil
+}
+
+// write a line for each gap, or with --result-only just its CIDRs
+func writeGaps(w io.Writer, gaps []gap) {
+	for _, g := range gaps {
+		if resultOnly {
+			for _, c := range g.CIDRs {
+				fmt.Fprintf(w, "%s\n", c)
+			}
+			continue
+		}
+		fmt.Fprintf(w, "%s-%s (%d addresses): %s\n", g.Start, g.End, g.Size, strings.Join(g.CIDRs, " "))
+	}
+}
+
+func init() {
+	RootCmd.AddCommand(gapsCmd)
+
+	gapsCmd.Flags().StringP("within", "w", "", "the supernet, e.g. 10.0.0.0/16")
+	gapsCmd.Flags().StringP("output", "o", "text", "output format: text, json or yaml")
+	addSplitFlag(gapsCmd)
+	gapsCmd.MarkFlagRequired("within")
+}
@@ -0,0 +1,98 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGaps(t *testing.T) {
+	supernet := parseBlocks(t, "10.0.0.0/22")[0]
+	allocations := parseBlocks(t, "10.0.0.64/26", "10.0.1.0/24", "10.0.2.0/25", "10.0.3.128/25")
+
+	got, err := gaps(supernet, allocations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []gap{
+		{"10.0.0.0", "10.0.0.63", 64, []string{"10.0.0.0/26"}},
+		{"10.0.0.128", "10.0.0.255", 128, []string{"10.0.0.128/25"}},
+		{"10.0.2.128", "10.0.3.127", 256, []string{"10.0.2.128/25", "10.0.3.0/25"}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("gaps = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i].Start != want[i].Start || got[i].End != want[i].End || got[i].Size != want[i].Size ||
+			strings.Join(got[i].CIDRs, " ") != strings.Join(want[i].CIDRs, " ") {
+			t.Errorf("gap %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	var out strings.Builder
+	writeGaps(&out, got)
+	text := "10.0.0.0-10.0.0.63 (64 addresses): 10.0.0.0/26\n" +
+		"10.0.0.128-10.0.0.255 (128 addresses): 10.0.0.128/25\n" +
+		"10.0.2.128-10.0.3.127 (256 addresses): 10.0.2.128/25 10.0.3.0/25\n"
+	if out.String() != text {
+		t.Errorf("writeGaps wrote\n%s\nwant\n%s", out.String(), text)
+	}
+}
+
+func TestGapsEdges(t *testing.T) {
+	supernet := parseBlocks(t, "10.0.0.0/24")[0]
+	tests := []struct {
+		allocations []string
+		want        string
+	}{
+		{nil, "10.0.0.0/24"},
+		{[]string{"10.0.0.0/24"}, ""},
+		{[]string{"10.0.0.0/25"}, "10.0.0.128/25"},
+		{[]string{"10.0.0.128/25"}, "10.0.0.0/25"},
+		// overlapping and unsorted allocations
+		{[]string{"10.0.0.128/26", "10.0.0.0/26", "10.0.0.0/27"}, "10.0.0.64/26 10.0.0.192/26"},
+	}
+
+	for _, tt := range tests {
+		got, err := gaps(supernet, parseBlocks(t, tt.allocations...))
+		if err != nil {
+			t.Errorf("gaps(%v): %s", tt.allocations, err)
+			continue
+		}
+		var cidrs []string
+		for _, g := range got {
+			cidrs = append(cidrs, g.CIDRs...)
+		}
+		if s := strings.Join(cidrs, " "); s != tt.want {
+			t.Errorf("gaps(%v) = %s, want %s", tt.allocations, s, tt.want)
+		}
+	}
+
+	if _, err := gaps(supernet, parseBlocks(t, "10.0.1.0/24")); err == nil {
+		t.Errorf("gaps accepted an allocation outside the supernet")
+	}
+}
+
+func TestGapsJSON(t *testing.T) {
+	got := runRoot(t, "gaps", "--within", "10.0.0.0/22", "--output", "json", "10.0.0.0/24", "10.0.2.0/25")
+	var report []gap
+	if err := json.Unmarshal([]byte(got), &report); err != nil {
+		t.Fatalf("gaps --output json printed %q: %s", got, err)
+	}
+	if len(report) != 2 || report[0].Start != "10.0.1.0" || report[1].Size != 384 {
+		t.Errorf("gaps --output json = %v", report)
+	}
+}
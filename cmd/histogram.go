@@ -0,0 +1,140 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// histogramCmd represents the histogram command
+var histogramCmd = &cobra.Command{
+	Use:   "histogram [cidr...]",
+	Short: "count the CIDRs of a list at each prefix length",
+	Long: `Print how many of the CIDRs have each prefix length, with a bar for
+each, to show the shape of a route dump at a glance.  The CIDRs are read
+from stdin, one per line, when none are given.  Example:
+
+	cidr histogram 10.0.0.0/24 10.0.1.0/24 10.1.0.0/16
+
+returns
+
+	/16  1  ##########################
+	/24  2  ####################################################
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+		group, err := cmd.Flags().GetBool("group-digits")
+		if err != nil {
+			panic(err)
+		}
+
+		var blocks []block
+		if len(args) > 0 {
+			for _, arg := range args {
+				b, err := parseBlock(arg)
+				if err != nil {
+					fmt.Printf("%s\n", err)
+					os.Exit(1)
+				}
+				blocks = append(blocks, b)
+			}
+		} else {
+			words, err := splitFlag(cmd)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+			blocks, err = readBlocks(os.Stdin, words)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+		}
+
+		counts := histogram(blocks)
+		if output == "text" {
+			err = writeHistogram(os.Stdout, counts, group)
+		} else {
+			byPrefix := make(map[string]int)
+			for prefix, n := range counts {
+				if n > 0 {
+					byPrefix[fmt.Sprintf("/%d", prefix)] = n
+				}
+			}
+			err = writeEncoded(os.Stdout, byPrefix, output)
+		}
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// the widest bar of a histogram
+const histogramWidth = 52
+
+// count the blocks at each prefix length
+func histogram(blocks []block) [33]int {
+	var counts [33]int
+	for _, b := range blocks {
+		counts[b.prefix]++
+	}
+	return counts
+}
+
+// write a line for each prefix length in use, in order, with a bar
+// scaled to the largest count.  group separates the count's digits.
+func writeHistogram(w io.Writer, counts [33]int, group bool) error {
+	most := 0
+	for _, n := range counts {
+		most = max(most, n)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for prefix, n := range counts {
+		if n == 0 {
+			continue
+		}
+		count := strconv.Itoa(n)
+		if group {
+			count = groupDigits(count)
+		}
+		if resultOnly {
+			fmt.Fprintf(tw, "/%d\t%s\n", prefix, count)
+			continue
+		}
+		bar := max(1, n*histogramWidth/most)
+		fmt.Fprintf(tw, "/%d\t%s\t%s\n", prefix, count, strings.Repeat("#", bar))
+	}
+	return tw.Flush()
+}
+
+func init() {
+	RootCmd.AddCommand(histogramCmd)
+
+	histogramCmd.Flags().StringP("output", "o", "text", "output format: text, json or yaml")
+	histogramCmd.Flags().Bool("group-digits", false, "separate the digits of text output counts with commas")
+	addSplitFlag(histogramCmd)
+}
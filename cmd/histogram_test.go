@@ -0,0 +1,74 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestHistogram(t *testing.T) {
+	blocks := parseBlocks(t,
+		"10.0.0.0/16",
+		"10.1.0.0/24", "10.1.1.0/24", "10.1.2.0/24", "10.1.3.0/24",
+		"10.2.0.0/26", "10.2.0.64/26",
+	)
+	counts := histogram(blocks)
+	for prefix, n := range counts {
+		want := map[int]int{16: 1, 24: 4, 26: 2}[prefix]
+		if n != want {
+			t.Errorf("%d blocks at /%d, want %d", n, prefix, want)
+		}
+	}
+
+	var out strings.Builder
+	if err := writeHistogram(&out, counts, false); err != nil {
+		t.Fatal(err)
+	}
+	want := "/16  1  " + strings.Repeat("#", 13) + "\n" +
+		"/24  4  " + strings.Repeat("#", histogramWidth) + "\n" +
+		"/26  2  " + strings.Repeat("#", 26) + "\n"
+	if out.String() != want {
+		t.Errorf("writeHistogram wrote\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestHistogramSmallCounts(t *testing.T) {
+	// a single block among thousands still gets a visible bar
+	var counts [33]int
+	counts[24] = 10000
+	counts[32] = 1
+
+	var out strings.Builder
+	if err := writeHistogram(&out, counts, true); err != nil {
+		t.Fatal(err)
+	}
+	want := "/24  10,000  " + strings.Repeat("#", histogramWidth) + "\n" +
+		"/32  1       #\n"
+	if out.String() != want {
+		t.Errorf("writeHistogram wrote\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestHistogramJSON(t *testing.T) {
+	got := runRoot(t, "histogram", "--output", "json", "10.0.0.0/24", "10.0.1.0/24", "10.1.0.0/16")
+	var counts map[string]int
+	if err := json.Unmarshal([]byte(got), &counts); err != nil {
+		t.Fatalf("histogram --output json printed %q: %s", got, err)
+	}
+	if len(counts) != 2 || counts["/16"] != 1 || counts["/24"] != 2 {
+		t.Errorf("histogram --output json = %v, want /16: 1 and /24: 2", counts)
+	}
+}
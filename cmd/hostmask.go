@@ -0,0 +1,103 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// hostmaskCmd represents the hostmask command
+var hostmaskCmd = &cobra.Command{
+	Use:   "hostmask",
+	Short: "print the bits of a mask which are left for the value",
+	Long: `Print the host mask: the bits of the address which the value fills
+in, as dotted decimal.  Unlike the within's wildcard, the host mask is
+rounded to the mask's field boundaries, so a field which the within
+fixes even partially is not host-assignable.  Example:
+
+	cidr hostmask --mask 8:13:4:7 --within 172.16.0.0/12
+
+returns
+
+	0.0.7.255
+
+where the within's wildcard would be 0.15.255.255.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		mask, err := cmd.Flags().GetString("mask")
+		if err != nil {
+			panic(err)
+		}
+		within, err := cmd.Flags().GetString("within")
+		if err != nil {
+			panic(err)
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+
+		hm, err := hostmask(mask, within)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		if output == "text" {
+			fmt.Printf("%s\n", hm.Hostmask)
+			return
+		}
+		if err := writeEncoded(os.Stdout, hm, output); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// a mask's host mask alongside the within's wildcard
+type hostmaskResult struct {
+	Hostmask string `json:"hostmask" yaml:"hostmask"`
+	Wildcard string `json:"wildcard" yaml:"wildcard"`
+	HostBits int    `json:"host_bits" yaml:"host_bits"`
+}
+
+// the host mask of the mask relative to the within
+func hostmask(mask, within string) (hostmaskResult, error) {
+	split, err := maskBits(mask, within)
+	if err != nil {
+		return hostmaskResult{}, err
+	}
+	fixed, err := withinPrefix(within, nil)
+	if err != nil {
+		return hostmaskResult{}, err
+	}
+
+	return hostmaskResult{
+		Hostmask: split.Hostmask,
+		Wildcard: formatIPv4(^prefixMask(fixed)),
+		HostBits: split.Host,
+	}, nil
+}
+
+func init() {
+	RootCmd.AddCommand(hostmaskCmd)
+
+	hostmaskCmd.Flags().StringP("mask", "m", "8:13:4:7", "bitmask whose host bits to print")
+	hostmaskCmd.Flags().StringP("within", "w", "0.0.0.0", "the within, optionally with a /prefix")
+	hostmaskCmd.Flags().StringP("output", "o", "text", "output format: text, json or yaml")
+}
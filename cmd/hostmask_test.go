@@ -0,0 +1,57 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHostmask(t *testing.T) {
+	tests := []struct {
+		mask, within       string
+		hostmask, wildcard string
+		hostBits           int
+	}{
+		{"12.8.6.6", "172.16.0.0/12", "0.15.255.255", "0.15.255.255", 20},
+		// rounded up to the end of the 13 bit field
+		{"8:13:4:7", "172.16.0.0/12", "0.0.7.255", "0.15.255.255", 11},
+		{"8.8.8.8", "10.0.0.0", "0.255.255.255", "0.255.255.255", 24},
+		{"16.16", "192.168.0.0/20", "0.0.0.0", "0.0.15.255", 0},
+	}
+
+	for _, tt := range tests {
+		got, err := hostmask(tt.mask, tt.within)
+		if err != nil {
+			t.Errorf("hostmask(%q, %q): %s", tt.mask, tt.within, err)
+			continue
+		}
+		want := hostmaskResult{Hostmask: tt.hostmask, Wildcard: tt.wildcard, HostBits: tt.hostBits}
+		if got != want {
+			t.Errorf("hostmask(%q, %q) = %+v, want %+v", tt.mask, tt.within, got, want)
+		}
+	}
+}
+
+func TestHostmaskJSON(t *testing.T) {
+	out := runRoot(t, "hostmask", "--mask", "8:13:4:7", "--within", "172.16.0.0/12", "--output", "json")
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid json %q -- %s", out, err)
+	}
+	if got["hostmask"] != "0.0.7.255" || got["wildcard"] != "0.15.255.255" || got["host_bits"] != 11.0 {
+		t.Errorf("unexpected json %v", got)
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// a block is an aligned IPv4 network, e.g. 10.0.0.0/24
+type block struct {
+	base   uint32
+	prefix int
+}
+
+// an ipRange is an inclusive span of IPv4 addresses
+type ipRange struct {
+	first, last uint32
+}
+
+// parse a dotted IPv4 address into its 32 bit value
+func parseIPv4(s string) (uint32, error) {
+	ip := net.ParseIP(strings.TrimSpace(s))
+	if ip == nil || ip.To4() == nil {
+		return 0, fmt.Errorf("'%s' is not a valid IPv4 address", s)
+	}
+	ip = ip.To4()
+
+	return uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3]), nil
+}
+
+// format a 32 bit value as a dotted IPv4 address
+func formatIPv4(addr uint32) string {
+	return fmt.Sprintf("%d.%d.%d.%d",
+		addr>>24,
+		(addr>>16)&0x0ff,
+		(addr>>8)&0x0ff,
+		addr&0x0ff)
+}
+
+// combine the four octets returned by computeCIDR into a 32 bit value
+func octetsToIPv4(octets []int) uint32 {
+	var addr uint32
+	for _, o := range octets {
+		addr = addr<<8 | uint32(o&0x0ff)
+	}
+	return addr
+}
+
+// parse a CIDR such as 10.0.0.0/24.  host bits are discarded.
+func parseBlock(s string) (block, error) {
+	s = strings.TrimSpace(s)
+	i := strings.IndexByte(s, '/')
+	if i < 0 {
+		return block{}, fmt.Errorf("'%s' is not in CIDR notation", s)
+	}
+
+	addr, err := parseIPv4(s[:i])
+	if err != nil {
+		return block{}, err
+	}
+
+	prefix, err := strconv.Atoi(s[i+1:])
+	if err != nil || prefix < 0 || prefix > 32 {
+		return block{}, fmt.Errorf("'%s' has an invalid prefix length", s)
+	}
+
+	return block{base: addr & prefixMask(prefix), prefix: prefix}, nil
+}
+
+// parse an address, a CIDR, or a dash separated range (10.0.0.1-10.0.0.9)
+func parseRange(s string) (ipRange, error) {
+	s = strings.TrimSpace(s)
+
+	if strings.Contains(s, "/") {
+		b, err := parseBlock(s)
+		if err != nil {
+			return ipRange{}, err
+		}
+		return b.span(), nil
+	}
+
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		first, err := parseIPv4(s[:i])
+		if err != nil {
+			return ipRange{}, err
+		}
+		last, err := parseIPv4(s[i+1:])
+		if err != nil {
+			return ipRange{}, err
+		}
+		if last < first {
+			return ipRange{}, fmt.Errorf("the range '%s' ends before it starts", s)
+		}
+		return ipRange{first: first, last: last}, nil
+	}
+
+	addr, err := parseIPv4(s)
+	if err != nil {
+		return ipRange{}, err
+	}
+	return ipRange{first: addr, last: addr}, nil
+}
+
+// return a netmask with the top 'prefix' bits set
+func prefixMask(prefix int) uint32 {
+	if prefix <= 0 {
+		return 0
+	}
+	return ^uint32(0) << uint(32-prefix)
+}
+
+// the number of addresses in the block
+func (b block) size() uint64 {
+	return uint64(1) << uint(32-b.prefix)
+}
+
+// the last address in the block
+func (b block) last() uint32 {
+	return b.base | ^prefixMask(b.prefix)
+}
+
+// true if o lies entirely within the block
+func (b block) contains(o block) bool {
+	return b.prefix <= o.prefix && o.base&prefixMask(b.prefix) == b.base
+}
+
+// the block as an inclusive range of addresses
+func (b block) span() ipRange {
+	return ipRange{first: b.base, last: b.last()}
+}
+
+func (b block) String() string {
+	return fmt.Sprintf("%s/%d", formatIPv4(b.base), b.prefix)
+}
+
+// the number of addresses in the range
+func (r ipRange) size() uint64 {
+	return uint64(r.last) - uint64(r.first) + 1
+}
+
+// true if the two ranges share any address
+func (r ipRange) overlaps(o ipRange) bool {
+	return r.first <= o.last && o.first <= r.last
+}
+
+// split the range into the fewest aligned blocks which exactly cover it
+func (r ipRange) blocks() []block {
+	var result []block
+
+	start := uint64(r.first)
+	end := uint64(r.last) + 1
+	for start < end {
+		prefix := 32
+		for prefix > 0 {
+			size := uint64(1) << uint(32-prefix+1)
+			if start%size != 0 || start+size > end {
+				break
+			}
+			prefix--
+		}
+
+		b := block{base: uint32(start), prefix: prefix}
+		result = append(result, b)
+		start += b.size()
+	}
+
+	return result
+}
+
+// sort the ranges and combine any which overlap or abut
+func mergeRanges(ranges []ipRange) []ipRange {
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	sorted := make([]ipRange, len(ranges))
+	copy(sorted, ranges)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].first < sorted[j].first
+	})
+
+	result := []ipRange{sorted[0]}
+	for _, r := range sorted[1:] {
+		tail := &result[len(result)-1]
+		if uint64(r.first) <= uint64(tail.last)+1 {
+			if r.last > tail.last {
+				tail.last = r.last
+			}
+			continue
+		}
+		result = append(result, r)
+	}
+
+	return result
+}
+
+// return the minimal set of blocks covering all of the ranges
+func summarize(ranges []ipRange) []block {
+	var result []block
+	for _, r := range mergeRanges(ranges) {
+		result = append(result, r.blocks()...)
+	}
+	return result
+}
@@ -0,0 +1,154 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"net/netip"
+	"strings"
+)
+
+// select the address family.  with "auto", an IPv6 within selects IPv6
+// and anything else is treated as IPv4.  the value isn't consulted: its
+// fields are packed into the mask rather than read as an address.
+func parseFamily(name, within string) (int, error) {
+	switch name {
+	case "4", "ipv4":
+		return 4, nil
+	case "6", "ipv6":
+		return 6, nil
+	case "auto":
+		if i := strings.IndexByte(within, '/'); i >= 0 {
+			within = within[:i]
+		}
+		if strings.Contains(within, ":") {
+			if addr, err := netip.ParseAddr(within); err == nil && addr.Is6() {
+				return 6, nil
+			}
+		}
+		return 4, nil
+	}
+	return 0, fmt.Errorf("unknown address family '%s', expected auto, 4 or 6", name)
+}
+
+// parse an IPv6 within.  the IPv4 default of 0.0.0.0 is read as ::
+// a zone (fe80::%eth0) is kept with the address.
+func parseWithin6(within string) (netip.Addr, error) {
+	if within == "0.0.0.0" {
+		return netip.IPv6Unspecified(), nil
+	}
+
+	addr, err := netip.ParseAddr(within)
+	if err != nil || !addr.Is6() {
+		return netip.Addr{}, fmt.Errorf("'%s' is not a valid IPv6 within", within)
+	}
+	return addr, nil
+}
+
+// pack the value into a 128 bit mask's fields and OR in the within
+func pack6(value, mask, within string, opts translateOptions) (netip.Addr, error) {
+	fields, values, _, err := packFields(value, mask, opts)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	result, err := computeCIDR6(fields, values, opts.lsbFirst)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	within, fixed, err := splitWithin(within, 128)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	if err := checkFixedBits(netip.AddrFrom16(result), within, fixed); err != nil {
+		return netip.Addr{}, err
+	}
+
+	w, err := parseWithin6(within)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+	wb := w.As16()
+	for i := range result {
+		result[i] |= wb[i]
+	}
+
+	// the within's zone, if any, carries over to the result
+	addr := netip.AddrFrom16(result).WithZone(w.Zone())
+	logger.Debug("combined with within", "within", w, "result", addr)
+
+	return addr, nil
+}
+
+// computeCIDR for 128 bit addresses
+func computeCIDR6(fields, values []int, lsbFirst bool) ([16]byte, error) {
+	var addr [16]byte
+
+	result := new(big.Int)
+	for n := range fields {
+		i := n
+		if lsbFirst {
+			i = len(fields) - n - 1
+		}
+		f := fields[i]
+
+		uval := big.NewInt(int64(values[i]))
+		if values[i] < 0 || uval.BitLen() > f {
+			max := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), uint(f)), big.NewInt(1))
+			return addr, fmt.Errorf("field #%d (%d) exceeds max %s for width %d", i, values[i], max, f)
+		}
+
+		result.Lsh(result, uint(f))
+		result.Or(result, uval)
+		if result.BitLen() > 128 {
+			return addr, fmt.Errorf("the value does not fit in a 128 bit address; the leading %d bits of the mask must be zero", sumFields(fields)-128)
+		}
+	}
+	logger.Debug("computeCIDR6", "fields", fields, "values", values, "result", fmt.Sprintf("0x%032x", result))
+
+	result.FillBytes(addr[:])
+	return addr, nil
+}
+
+// render an address, compressed per RFC 5952 unless the full form is
+// requested, and in uppercase hex if requested.  a zone keeps its case.
+func formatAddr(addr netip.Addr, opts translateOptions) string {
+	if !addr.Is6() || !opts.uppercase && !opts.expand {
+		return addr.String()
+	}
+
+	str := addr.WithZone("").String()
+	if opts.expand {
+		str = addr.WithZone("").StringExpanded()
+	}
+	if opts.uppercase {
+		str = strings.ToUpper(str)
+	}
+	if zone := addr.Zone(); len(zone) > 0 {
+		str += "%" + zone
+	}
+	return str
+}
+
+// render a prefix with formatAddr, separating the prefix length with
+// the --prefix-delimiter
+func formatPrefix(prefix netip.Prefix, opts translateOptions) string {
+	delim := opts.prefixDelimiter
+	if len(delim) == 0 {
+		delim = "/"
+	}
+	return fmt.Sprintf("%s%s%d", formatAddr(prefix.Addr(), opts), delim, prefix.Bits())
+}
@@ -0,0 +1,209 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestParseFamily(t *testing.T) {
+	tests := []struct {
+		name, within string
+		want         int
+	}{
+		// unambiguous IPv4
+		{"auto", "10.0.0.0", 4},
+		{"auto", "172.16.0.0/12", 4},
+		// unambiguous IPv6
+		{"auto", "2001:db8::", 6},
+		{"auto", "2001:db8::/32", 6},
+		// ambiguous, which falls back to IPv4
+		{"auto", "0.0.0.0", 4},
+		{"auto", "1:2", 4},
+		// forced
+		{"6", "0.0.0.0", 6},
+		{"ipv6", "0.0.0.0", 6},
+		{"4", "2001:db8::", 4},
+		{"ipv4", "2001:db8::", 4},
+	}
+
+	for _, tt := range tests {
+		got, err := parseFamily(tt.name, tt.within)
+		if err != nil {
+			t.Errorf("parseFamily(%q, %q): %s", tt.name, tt.within, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseFamily(%q, %q) = %d, want %d", tt.name, tt.within, got, tt.want)
+		}
+	}
+
+	if _, err := parseFamily("5", "0.0.0.0"); err == nil {
+		t.Error("expected an unknown family to be rejected")
+	}
+}
+
+func TestFamilyDetection(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--mask", "8.8.8.8", "--within", "10.0.0.0", "0.1.2.3"}, "10.1.2.3\n"},
+		{[]string{"--mask", "8.8.8.8", "--within", "10.0.0.0", "0:1:2:3"}, "10.1.2.3\n"},
+		{[]string{"--mask", "64.64", "--within", "2001:db8::", "1.5"}, "2001:db8:0:1::5\n"},
+		{[]string{"--family", "6", "--mask", "64.64", "1.5"}, "::1:0:0:0:5\n"},
+	}
+
+	for _, tt := range tests {
+		if got := runRoot(t, tt.args...); got != tt.want {
+			t.Errorf("cidr %v printed %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestZones(t *testing.T) {
+	tests := []struct {
+		within string
+		want   string
+	}{
+		{"fe80::", "fe80:0:0:1::5"},
+		{"fe80::%eth0", "fe80:0:0:1::5%eth0"},
+		{"fe80::%en0", "fe80:0:0:1::5%en0"},
+	}
+
+	for _, tt := range tests {
+		got, err := translate("1.5", "64.64", tt.within, translateOptions{family: 6})
+		if err != nil {
+			t.Errorf("translate within %q: %s", tt.within, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("translate within %q = %s, want %s", tt.within, got, tt.want)
+		}
+
+		// the zone survives a round trip through the standard library
+		addr, err := netip.ParseAddr(got)
+		if err != nil {
+			t.Errorf("netip.ParseAddr(%q): %s", got, err)
+			continue
+		}
+		w, err := parseWithin6(tt.within)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr.Zone() != w.Zone() {
+			t.Errorf("%s has the zone %q, want %q", got, addr.Zone(), w.Zone())
+		}
+	}
+
+	// a prefix can't carry a zone, so it is dropped
+	prefix, err := translatePrefix("1.5", "64.64", "fe80::%eth0", 64, translateOptions{family: 6})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prefix.String() != "fe80:0:0:1::5/64" {
+		t.Errorf("got the prefix %s, want fe80:0:0:1::5/64", prefix)
+	}
+}
+
+func TestMixedCaseIPv6(t *testing.T) {
+	for _, within := range []string{"2001:db8:abcd::", "2001:DB8:ABCD::", "2001:Db8:aBcD::"} {
+		lower, err := translate("0xff.0xEF", "64.64", within, translateOptions{family: 6})
+		if err != nil {
+			t.Errorf("translate within %q: %s", within, err)
+			continue
+		}
+		if lower != "2001:db8:abcd:ff::ef" {
+			t.Errorf("translate within %q = %s, want 2001:db8:abcd:ff::ef", within, lower)
+		}
+
+		upper, err := translate("0xff.0xEF", "64.64", within, translateOptions{family: 6, uppercase: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if upper != "2001:DB8:ABCD:FF::EF" {
+			t.Errorf("--uppercase within %q = %s, want 2001:DB8:ABCD:FF::EF", within, upper)
+		}
+
+		// both forms parse back to the same address
+		a, err := netip.ParseAddr(lower)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := netip.ParseAddr(upper)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a != b {
+			t.Errorf("%s and %s are different addresses", lower, upper)
+		}
+	}
+}
+
+func TestAbbreviate(t *testing.T) {
+	tests := []struct {
+		addr, compressed, full string
+	}{
+		{"2001:db8::1", "2001:db8::1", "2001:0db8:0000:0000:0000:0000:0000:0001"},
+		{"2001:db8:0:0:1:0:0:1", "2001:db8::1:0:0:1", "2001:0db8:0000:0000:0001:0000:0000:0001"},
+		{"::", "::", "0000:0000:0000:0000:0000:0000:0000:0000"},
+		{"fe80::1%eth0", "fe80::1%eth0", "fe80:0000:0000:0000:0000:0000:0000:0001%eth0"},
+	}
+
+	for _, tt := range tests {
+		addr := netip.MustParseAddr(tt.addr)
+		if got := formatAddr(addr, translateOptions{}); got != tt.compressed {
+			t.Errorf("compressed %s = %s, want %s", tt.addr, got, tt.compressed)
+		}
+		if got := formatAddr(addr, translateOptions{expand: true}); got != tt.full {
+			t.Errorf("full %s = %s, want %s", tt.addr, got, tt.full)
+		}
+		if got := formatAddr(addr, translateOptions{expand: true, uppercase: true}); got != strings.ToUpper(tt.full[:39])+tt.full[39:] {
+			t.Errorf("full uppercase %s = %s", tt.addr, got)
+		}
+	}
+
+	got := runRoot(t, "--mask", "64.64", "--within", "2001:db8::", "--abbreviate", "full", "1.5")
+	if got != "2001:0db8:0000:0001:0000:0000:0000:0005\n" {
+		t.Errorf("--abbreviate full printed %q", got)
+	}
+}
+
+func TestPrefixDelimiter(t *testing.T) {
+	prefix := netip.MustParsePrefix("172.16.0.0/12")
+	tests := []struct {
+		delim, want string
+	}{
+		{"", "172.16.0.0/12"},
+		{"/", "172.16.0.0/12"},
+		{" ", "172.16.0.0 12"},
+		{"\t", "172.16.0.0\t12"},
+	}
+
+	for _, tt := range tests {
+		if got := formatPrefix(prefix, translateOptions{prefixDelimiter: tt.delim}); got != tt.want {
+			t.Errorf("formatPrefix with delimiter %q = %q, want %q", tt.delim, got, tt.want)
+		}
+	}
+
+	got := runRoot(t, "--mask", "12.8.6.6", "--within", "172.16.0.0", "--prefix", "26", "--prefix-delimiter", " ", "0.1.1.1")
+	if got != "172.16.16.65 26\n" {
+		t.Errorf("--prefix-delimiter ' ' printed %q", got)
+	}
+	if got := runRoot(t, "--mask", "12.8.6.6", "--within", "172.16.0.0", "--prefix", "26", "0.1.1.1"); got != "172.16.16.65/26\n" {
+		t.Errorf("the default delimiter printed %q", got)
+	}
+}
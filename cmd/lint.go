@@ -0,0 +1,203 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	yaml "gopkg.in/yaml.v3"
+)
+
+// lintCmd represents the lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint <spec>",
+	Short: "check an allocation plan for overlaps, strays, gaps and oversubscription",
+	Long: `Read a YAML or JSON file describing a supernet and the blocks
+planned within it, and report its problems.  Overlapping blocks, blocks
+outside the supernet, a block too small for the hosts it plans for, and
+allocations adding up to more than the supernet are errors; unallocated
+gaps are warnings.  Exits 1 if there are any errors.  With
+--result-only, each problem is printed without its level.  Example:
+
+	cidr lint plan.yaml
+
+where plan.yaml is
+
+	supernet: 10.0.0.0/22
+	allocations:
+	  - name: web
+	    cidr: 10.0.0.0/24
+	    hosts: 300
+	  - name: db
+	    cidr: 10.0.0.128/25
+
+returns
+
+	error: web 10.0.0.0/24 plans for 300 hosts but holds only 254
+	error: web 10.0.0.0/24 overlaps db 10.0.0.128/25
+	warning: 10.0.1.0-10.0.3.255 (768 addresses) is unallocated
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+
+		f, err := os.Open(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		defer f.Close()
+
+		spec, err := readSpec(f)
+		if err != nil {
+			fmt.Printf("%s: %s\n", args[0], err)
+			os.Exit(1)
+		}
+
+		issues, err := lint(spec)
+		if err != nil {
+			fmt.Printf("%s: %s\n", args[0], err)
+			os.Exit(1)
+		}
+
+		if output == "text" {
+			for _, i := range issues {
+				if resultOnly {
+					fmt.Printf("%s\n", i.Message)
+					continue
+				}
+				fmt.Printf("%s: %s\n", i.Level, i.Message)
+			}
+		} else if err := writeEncoded(os.Stdout, issues, output); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		for _, i := range issues {
+			if i.Level == "error" {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+// an allocation plan: a supernet and the blocks planned within it
+type allocSpec struct {
+	Supernet    string `json:"supernet" yaml:"supernet"`
+	Allocations []struct {
+		Name  string `json:"name" yaml:"name"`
+		CIDR  string `json:"cidr" yaml:"cidr"`
+		Hosts uint64 `json:"hosts" yaml:"hosts"`
+	} `json:"allocations" yaml:"allocations"`
+}
+
+// a problem found in a spec, at the error or warning level
+type lintIssue struct {
+	Level   string `json:"level" yaml:"level"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// read a spec; YAML is a superset of JSON, so this reads either
+func readSpec(r io.Reader) (allocSpec, error) {
+	var spec allocSpec
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	if err := dec.Decode(&spec); err != nil {
+		return allocSpec{}, fmt.Errorf("can't parse the spec -- %s", err)
+	}
+	return spec, nil
+}
+
+// report the spec's problems: the errors of each allocation, then
+// overlaps and oversubscription, then gaps as warnings
+func lint(spec allocSpec) ([]lintIssue, error) {
+	supernet, err := parseBlock(spec.Supernet)
+	if err != nil {
+		return nil, fmt.Errorf("supernet: %s", err)
+	}
+
+	issues := []lintIssue{}
+	report := func(level, format string, a ...any) {
+		issues = append(issues, lintIssue{Level: level, Message: fmt.Sprintf(format, a...)})
+	}
+
+	blocks := make([]block, len(spec.Allocations))
+	labels := make([]string, len(blocks))
+	var inside []block
+	var sum uint64
+	for i, a := range spec.Allocations {
+		b, err := parseBlock(a.CIDR)
+		if err != nil {
+			return nil, fmt.Errorf("allocation #%d: %s", i, err)
+		}
+		blocks[i] = b
+
+		label := b.String()
+		if len(a.Name) > 0 {
+			label = a.Name + " " + label
+		}
+		labels[i] = label
+
+		if !supernet.contains(b) {
+			report("error", "%s is not within %s", label, supernet)
+		} else {
+			inside = append(inside, b)
+			sum += b.size()
+		}
+		if hosts := usableHosts(b.prefix); a.Hosts > hosts {
+			report("error", "%s plans for %d hosts but holds only %d", label, a.Hosts, hosts)
+		}
+	}
+
+	// by index rather than with overlaps, so a block planned twice is
+	// reported by the names of both
+	for i, a := range blocks {
+		for j := i + 1; j < len(blocks); j++ {
+			if a.span().overlaps(blocks[j].span()) {
+				report("error", "%s overlaps %s", labels[i], labels[j])
+			}
+		}
+	}
+
+	if sum > supernet.size() {
+		report("error", "the allocations within %s add up to %d addresses, more than its %d", supernet, sum, supernet.size())
+	}
+
+	free, err := gaps(supernet, inside)
+	if err != nil {
+		return nil, err
+	}
+	for _, g := range free {
+		report("warning", "%s-%s (%d addresses) is unallocated", g.Start, g.End, g.Size)
+	}
+
+	return issues, nil
+}
+
+func init() {
+	RootCmd.AddCommand(lintCmd)
+
+	lintCmd.Flags().StringP("output", "o", "text", "output format: text, json or yaml")
+}
@@ -0,0 +1,151 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// lint the spec, returning its issues as "level: message" lines
+func lintString(t *testing.T, text string) []string {
+	t.Helper()
+
+	spec, err := readSpec(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	issues, err := lint(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := []string{}
+	for _, i := range issues {
+		lines = append(lines, i.Level+": "+i.Message)
+	}
+	return lines
+}
+
+func TestLintValid(t *testing.T) {
+	specs := []string{
+		`supernet: 10.0.0.0/22
+allocations:
+  - name: web
+    cidr: 10.0.0.0/24
+    hosts: 200
+  - name: db
+    cidr: 10.0.1.0/24
+  - cidr: 10.0.2.0/23
+`,
+		`{"supernet": "10.0.0.0/23", "allocations": [
+			{"name": "web", "cidr": "10.0.0.0/24", "hosts": 254},
+			{"name": "db", "cidr": "10.0.1.0/24"}]}`,
+	}
+
+	for _, spec := range specs {
+		if issues := lintString(t, spec); len(issues) != 0 {
+			t.Errorf("lint reported %v for the valid spec\n%s", issues, spec)
+		}
+	}
+}
+
+func TestLintInvalid(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []string
+	}{
+		{`supernet: 10.0.0.0/22
+allocations:
+  - name: web
+    cidr: 10.0.0.0/24
+    hosts: 300
+  - name: db
+    cidr: 10.0.0.128/25
+`, []string{
+			"error: web 10.0.0.0/24 plans for 300 hosts but holds only 254",
+			"error: web 10.0.0.0/24 overlaps db 10.0.0.128/25",
+			"warning: 10.0.1.0-10.0.3.255 (768 addresses) is unallocated",
+		}},
+		{`supernet: 10.0.0.0/24
+allocations:
+  - name: web
+    cidr: 10.0.0.0/24
+  - name: stray
+    cidr: 10.0.1.0/24
+`, []string{
+			"error: stray 10.0.1.0/24 is not within 10.0.0.0/24",
+		}},
+		{`supernet: 10.0.0.0/24
+allocations:
+  - name: a
+    cidr: 10.0.0.0/24
+  - name: b
+    cidr: 10.0.0.0/24
+`, []string{
+			"error: a 10.0.0.0/24 overlaps b 10.0.0.0/24",
+			"error: the allocations within 10.0.0.0/24 add up to 512 addresses, more than its 256",
+		}},
+		{`supernet: 10.0.0.0/24
+allocations:
+  - cidr: 10.0.0.64/26
+`, []string{
+			"warning: 10.0.0.0-10.0.0.63 (64 addresses) is unallocated",
+			"warning: 10.0.0.128-10.0.0.255 (128 addresses) is unallocated",
+		}},
+	}
+
+	for _, tt := range tests {
+		got := lintString(t, tt.spec)
+		if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("lint of\n%s\nreported\n%s\nwant\n%s", tt.spec, strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+		}
+	}
+}
+
+func TestLintUnreadable(t *testing.T) {
+	tests := []struct {
+		spec, want string
+	}{
+		{"supernet: 10.0.0.0/24\nallocation: []\n", "can't parse the spec"},
+		{"supernet: 10.0.0.0/33\n", "supernet:"},
+		{"supernet: 10.0.0.0/24\nallocations:\n  - cidr: bogus\n", "allocation #0:"},
+	}
+
+	for _, tt := range tests {
+		spec, err := readSpec(strings.NewReader(tt.spec))
+		if err == nil {
+			_, err = lint(spec)
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("lint of %q = %v, want %q", tt.spec, err, tt.want)
+		}
+	}
+}
+
+func TestLintCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.yaml")
+	spec := "supernet: 10.0.0.0/23\nallocations:\n  - name: web\n    cidr: 10.0.0.0/24\n"
+	if err := os.WriteFile(path, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := runRoot(t, "lint", path), "warning: 10.0.1.0-10.0.1.255 (256 addresses) is unallocated\n"; got != want {
+		t.Errorf("lint printed %q, want %q", got, want)
+	}
+	if got, want := runRoot(t, "--result-only", "lint", path), "10.0.1.0-10.0.1.255 (256 addresses) is unallocated\n"; got != want {
+		t.Errorf("lint --result-only printed %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+var (
+	logLevel string
+
+	// the logger's level.  initLogging sets it rather than replacing the
+	// logger, so a translation running concurrently never sees a torn logger.
+	logLevelVar slog.LevelVar
+	logger      = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: &logLevelVar}))
+)
+
+func init() {
+	logLevelVar.Set(slog.LevelWarn)
+}
+
+// convert a --log-level name into a slog.Level
+func parseLogLevel(name string) (slog.Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return 0, fmt.Errorf("unknown log level '%s', expected debug, info, warn or error", name)
+}
+
+// initLogging configures the logger from the --log-level flag
+func initLogging() {
+	level, err := parseLogLevel(logLevel)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	logLevelVar.Set(level)
+}
@@ -0,0 +1,285 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// format field widths as a dotted mask
+func formatMask(fields []int) string {
+	str := make([]string, len(fields))
+	for i, f := range fields {
+		str[i] = strconv.Itoa(f)
+	}
+	return strings.Join(str, ".")
+}
+
+// the prefix length of a contiguous netmask such as 255.255.240.0
+func netmaskPrefix(netmask string) (int, error) {
+	value, err := parseIPv4(netmask)
+	if err != nil {
+		return 0, err
+	}
+
+	// a contiguous netmask's complement is a run of low-order 1's
+	host := ^value
+	if host&(host+1) != 0 {
+		return 0, fmt.Errorf("the netmask '%s' is not contiguous", netmask)
+	}
+
+	return bits.OnesCount32(value), nil
+}
+
+// true if the fields are the octets of a contiguous netmask, e.g. a
+// netmask mistakenly passed where field widths were expected
+func looksLikeNetmask(fields []int) bool {
+	if len(fields) != 4 {
+		return false
+	}
+	sum := 0
+	for _, f := range fields {
+		if f < 0 || f > 255 {
+			return false
+		}
+		sum += f
+	}
+	if sum <= 32 {
+		return false
+	}
+
+	_, err := netmaskPrefix(formatMask(fields))
+	return err == nil
+}
+
+// drop a trailing # comment from a mask, e.g. "12.8.6.6 # regional
+// scheme", so annotated masks may be stored in config files
+func stripMaskComment(mask string) string {
+	if i := strings.IndexByte(mask, '#'); i >= 0 {
+		mask = mask[:i]
+	}
+	return strings.TrimSpace(mask)
+}
+
+// read a mask from a file: the first line which isn't blank or a
+// comment, with any trailing comment removed
+func readMaskFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading mask file -- %s", err)
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		if mask := stripMaskComment(line); len(mask) > 0 {
+			return mask, nil
+		}
+	}
+	return "", fmt.Errorf("the mask file %s contains no mask", path)
+}
+
+// derive a field layout from a netmask such as 255.255.240.0
+func deriveMask(netmask string) (string, error) {
+	prefix, err := netmaskPrefix(netmask)
+	if err != nil {
+		return "", err
+	}
+	return maskFromPrefix(prefix)
+}
+
+// build a field layout for a prefix length.  the network bits are split
+// on octet boundaries and the host bits form the final field, so /20
+// becomes 8.8.4.12 and /24 becomes 8.8.8.8
+func maskFromPrefix(prefix int) (string, error) {
+	if prefix < 0 || prefix > 32 {
+		return "", fmt.Errorf("invalid prefix length %d, expected 0-32", prefix)
+	}
+
+	var fields []int
+	for n := prefix; n > 0; n -= 8 {
+		if n >= 8 {
+			fields = append(fields, 8)
+		} else {
+			fields = append(fields, n)
+		}
+	}
+	if prefix == 0 {
+		// parse needs at least two fields
+		fields = append(fields, 0)
+	}
+	if prefix < 32 {
+		fields = append(fields, 32-prefix)
+	}
+
+	return formatMask(fields), nil
+}
+
+// a named mask is a comma separated list of width:name fields, e.g.
+// 12:region,8:pod,6:rack,6:host.  names are optional per field, and a
+// field may limit its values to a range, e.g. 8:pod[0-200].
+var namedField = regexp.MustCompile(`^\s*(\d+)(?::([A-Za-z_][A-Za-z0-9_-]*))?(?:\[(\d+)-(\d+)\])?\s*$`)
+
+// the values a field of a named mask allows
+type fieldRange struct {
+	set      bool
+	min, max int
+}
+
+// true if the mask uses the width:name form
+func isNamedMask(mask string) bool {
+	if !strings.Contains(mask, ",") || !strings.Contains(mask, ":") {
+		return false
+	}
+	for _, f := range strings.Split(mask, ",") {
+		if !namedField.MatchString(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// split a named mask into its widths and names
+func parseFieldNames(mask string) ([]int, []string, error) {
+	parts := strings.Split(mask, ",")
+	fields := make([]int, len(parts))
+	names := make([]string, len(parts))
+
+	for i, p := range parts {
+		m := namedField.FindStringSubmatch(p)
+		if m == nil {
+			return nil, nil, fmt.Errorf("error parsing mask field '%s'", p)
+		}
+
+		var err error
+		fields[i], err = strconv.Atoi(m[1])
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing mask field '%s' -- %s", p, err)
+		}
+		names[i] = m[2]
+	}
+
+	return fields, names, nil
+}
+
+// the range of values each field of a named mask allows.  fields
+// without a range (and every field of other masks) are not set.
+func parseFieldRanges(mask string) ([]fieldRange, error) {
+	if !isNamedMask(mask) {
+		return nil, nil
+	}
+
+	parts := strings.Split(mask, ",")
+	ranges := make([]fieldRange, len(parts))
+	for i, p := range parts {
+		m := namedField.FindStringSubmatch(p)
+		if m == nil || len(m[3]) == 0 {
+			continue
+		}
+
+		lo, err := strconv.Atoi(m[3])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing mask field '%s' -- %s", p, err)
+		}
+		hi, err := strconv.Atoi(m[4])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing mask field '%s' -- %s", p, err)
+		}
+		if lo > hi {
+			return nil, fmt.Errorf("mask field '%s' has a range which ends before it starts", strings.TrimSpace(p))
+		}
+		ranges[i] = fieldRange{set: true, min: lo, max: hi}
+	}
+	return ranges, nil
+}
+
+// make sure each value lies in its field's allowed range
+func checkFieldRanges(values []int, names []string, ranges []fieldRange) error {
+	for i, r := range ranges {
+		if !r.set || i >= len(values) {
+			continue
+		}
+		if values[i] < r.min || values[i] > r.max {
+			return fmt.Errorf("%s (%d) is outside its allowed range %d-%d", fieldLabel(names, i), values[i], r.min, r.max)
+		}
+	}
+	return nil
+}
+
+// the label for a field in explain output: its name, or its index
+func fieldLabel(names []string, i int) string {
+	if i < len(names) && len(names[i]) > 0 {
+		return names[i]
+	}
+	return fmt.Sprintf("field #%d", i)
+}
+
+// describe each field's width and value, one per line
+func explainFields(w io.Writer, fields, values []int, names []string) {
+	for i, f := range fields {
+		fmt.Fprintf(w, "%s (%d bits) = %d\n", fieldLabel(names, i), f, values[i])
+	}
+}
+
+// build a dotted value from a JSON object whose keys are the names of
+// the mask's fields, e.g. {"region":0,"pod":1,"rack":1,"host":1}
+func valueFromJSON(input, mask string) (string, error) {
+	if !isNamedMask(mask) {
+		return "", fmt.Errorf("--json-input requires a mask with named fields")
+	}
+	_, names, err := parseFieldNames(mask)
+	if err != nil {
+		return "", err
+	}
+
+	dec := json.NewDecoder(strings.NewReader(input))
+	dec.UseNumber()
+	var obj map[string]json.Number
+	if err := dec.Decode(&obj); err != nil {
+		return "", fmt.Errorf("error parsing --json-input -- %s", err)
+	}
+
+	index := make(map[string]int, len(names))
+	for i, name := range names {
+		if len(name) > 0 {
+			index[name] = i
+		}
+	}
+
+	values := make([]int, len(names))
+	for key, num := range obj {
+		i, ok := index[key]
+		if !ok {
+			return "", fmt.Errorf("--json-input has the key '%s', which is not a field of the mask", key)
+		}
+		v, err := strconv.Atoi(num.String())
+		if err != nil || v < 0 {
+			return "", fmt.Errorf("--json-input field '%s' (%s) is not a non-negative integer", key, num)
+		}
+		values[i] = v
+	}
+
+	for i, name := range names {
+		if _, ok := obj[name]; !ok {
+			return "", fmt.Errorf("--json-input is missing the field '%s'", fieldLabel(names, i))
+		}
+	}
+
+	return formatMask(values), nil
+}
@@ -0,0 +1,310 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDeriveMask(t *testing.T) {
+	tests := []struct {
+		netmask string
+		want    string
+	}{
+		{"255.255.240.0", "8.8.4.12"},
+		{"255.255.255.0", "8.8.8.8"},
+		{"255.0.0.0", "8.24"},
+		{"255.255.255.255", "8.8.8.8"},
+		{"255.255.255.254", "8.8.8.7.1"},
+		{"0.0.0.0", "0.32"},
+	}
+
+	for _, tt := range tests {
+		got, err := deriveMask(tt.netmask)
+		if err != nil {
+			t.Errorf("deriveMask(%q): %s", tt.netmask, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("deriveMask(%q) = %s, want %s", tt.netmask, got, tt.want)
+		}
+		if _, err := validateMask(got); err != nil && tt.netmask != "0.0.0.0" {
+			t.Errorf("deriveMask(%q) gave an invalid mask %s -- %s", tt.netmask, got, err)
+		}
+	}
+}
+
+func TestDeriveMaskNonContiguous(t *testing.T) {
+	for _, netmask := range []string{"255.0.255.0", "255.255.255.1", "0.255.255.255"} {
+		if got, err := deriveMask(netmask); err == nil {
+			t.Errorf("deriveMask(%q) = %s, expected an error", netmask, got)
+		}
+	}
+}
+
+func TestParseNamedMask(t *testing.T) {
+	tests := []struct {
+		mask   string
+		fields []int
+		names  []string
+	}{
+		{"12:region,8:pod,6:rack,6:host", []int{12, 8, 6, 6}, []string{"region", "pod", "rack", "host"}},
+		{"12:region,8,6:rack,6", []int{12, 8, 6, 6}, []string{"region", "", "rack", ""}},
+		{"16:net, 16:host", []int{16, 16}, []string{"net", "host"}},
+		{"8:pod[0-200],24:host", []int{8, 24}, []string{"pod", "host"}},
+		{"12.8.6.6", []int{12, 8, 6, 6}, []string{"", "", "", ""}},
+		{"12.8.6.6 # regional", []int{12, 8, 6, 6}, []string{"", "", "", ""}},
+	}
+
+	for _, tt := range tests {
+		fields, names, err := parseNamedMask(tt.mask)
+		if err != nil {
+			t.Errorf("parseNamedMask(%q): %s", tt.mask, err)
+			continue
+		}
+		if !slices.Equal(fields, tt.fields) || !slices.Equal(names, tt.names) {
+			t.Errorf("parseNamedMask(%q) = %v %q, want %v %q", tt.mask, fields, names, tt.fields, tt.names)
+		}
+	}
+}
+
+func TestNamedMaskResultFields(t *testing.T) {
+	res, err := newResult("1.2.3.4", "12:region,8:pod,6:rack,6:host", "0.0.0.0", -1, translateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"region", "pod", "rack", "host"}
+	for i, f := range res.Fields {
+		if f.Name != want[i] {
+			t.Errorf("field #%d is named %q, want %q", i, f.Name, want[i])
+		}
+	}
+}
+
+func TestMaskFromPrefix(t *testing.T) {
+	tests := []struct {
+		prefix int
+		want   string
+	}{
+		{0, "0.32"},
+		{8, "8.24"},
+		{12, "8.4.20"},
+		{16, "8.8.16"},
+		{20, "8.8.4.12"},
+		{24, "8.8.8.8"},
+		{30, "8.8.8.6.2"},
+		{32, "8.8.8.8"},
+	}
+
+	for _, tt := range tests {
+		got, err := maskFromPrefix(tt.prefix)
+		if err != nil {
+			t.Errorf("maskFromPrefix(%d): %s", tt.prefix, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("maskFromPrefix(%d) = %s, want %s", tt.prefix, got, tt.want)
+		}
+	}
+
+	for _, prefix := range []int{-1, 33} {
+		if got, err := maskFromPrefix(prefix); err == nil {
+			t.Errorf("maskFromPrefix(%d) = %s, expected an error", prefix, got)
+		}
+	}
+}
+
+func TestNetmaskHint(t *testing.T) {
+	_, err := validateMask("255.255.255.0")
+	if err == nil {
+		t.Fatal("expected a netmask to be rejected as a mask")
+	}
+	if !strings.Contains(err.Error(), "did you mean a netmask? use --mask-type netmask") {
+		t.Errorf("expected the netmask hint, got %q", err)
+	}
+
+	// a mask which is simply too wide gets no hint
+	_, err = validateMask("16.16.16.16")
+	if err == nil || strings.Contains(err.Error(), "netmask") {
+		t.Errorf("expected an error without the netmask hint, got %v", err)
+	}
+}
+
+func TestValueFromJSON(t *testing.T) {
+	mask := "16:region,8:pod,7:rack,1:host"
+
+	got, err := valueFromJSON(`{"region":16,"pod":16,"rack":1,"host":1}`, mask)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "16.16.1.1" {
+		t.Errorf("got %s, want 16.16.1.1", got)
+	}
+
+	// keys in any order
+	got, err = valueFromJSON(`{"host":0,"rack":3,"pod":2,"region":1}`, mask)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "1.2.3.0" {
+		t.Errorf("got %s, want 1.2.3.0", got)
+	}
+
+	tests := []struct {
+		input, mask, want string
+	}{
+		{`{"region":1,"pod":1,"rack":1,"host":1,"zone":1}`, mask, "'zone', which is not a field"},
+		{`{"region":1,"pod":1,"rack":1}`, mask, "missing the field 'host'"},
+		{`{"region":-1,"pod":1,"rack":1,"host":1}`, mask, "not a non-negative integer"},
+		{`{"region":1.5,"pod":1,"rack":1,"host":1}`, mask, "not a non-negative integer"},
+		{`{"region":1,`, mask, "error parsing --json-input"},
+		{`{"region":1}`, "16.8.7.1", "requires a mask with named fields"},
+	}
+	for _, tt := range tests {
+		_, err := valueFromJSON(tt.input, tt.mask)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("valueFromJSON(%s) = %v, want an error containing %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestMaskComments(t *testing.T) {
+	tests := []struct {
+		mask, want string
+	}{
+		{"12.8.6.6 # regional scheme", "12.8.6.6"},
+		{"12.8.6.6#tight", "12.8.6.6"},
+		{"  12.8.6.6  ", "12.8.6.6"},
+		{"12:region,20:host # named", "12:region,20:host"},
+		{"# nothing but a comment", ""},
+	}
+	for _, tt := range tests {
+		if got := stripMaskComment(tt.mask); got != tt.want {
+			t.Errorf("stripMaskComment(%q) = %q, want %q", tt.mask, got, tt.want)
+		}
+	}
+
+	// a commented mask translates like the bare one
+	got, err := translate("1.1.1.1", "12.8.6.6 # regional scheme", "0.0.0.0", translateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "0.16.16.65" {
+		t.Errorf("got %s, want 0.16.16.65", got)
+	}
+}
+
+func TestReadMaskFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mask")
+	if err := os.WriteFile(path, []byte("# the regional scheme\n\n12.8.6.6 # region.pod.rack.host\n8.8.8.8\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := readMaskFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "12.8.6.6" {
+		t.Errorf("readMaskFile = %q, want 12.8.6.6", got)
+	}
+
+	if err := os.WriteFile(path, []byte("# only comments\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := readMaskFile(path); err == nil {
+		t.Errorf("readMaskFile = %q, expected an error for a file without a mask", got)
+	}
+}
+
+func TestMaskFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scheme.mask")
+	if err := os.WriteFile(path, []byte("12.8.6.6\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := runRoot(t, "--mask-file", path, "--within", "172.16.0.0", "0.1.1.1")
+	if got != "172.16.16.65\n" {
+		t.Errorf("got %q, want 172.16.16.65", got)
+	}
+}
+
+func TestMaskFileExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "scheme.mask")
+	if err := os.WriteFile(path, []byte("12.8.6.6\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := runRoot(t, "--mask-file", path, "--mask", "8.8.8.8", "0.1.1.1")
+	if !strings.Contains(got, "only one of --mask, --mask-file") {
+		t.Errorf("expected --mask-file and --mask to be refused together, got %q", got)
+	}
+}
+
+func TestFieldRanges(t *testing.T) {
+	const mask = "12:region,8:pod[0-200],6:rack[1-60],6"
+	tests := []struct {
+		value string
+		want  string // the result, or a substring of the error
+	}{
+		{"0.1.1.1", "172.16.16.65"},
+		{"0.200.1.1", "172.28.128.65"},
+		{"0.0.60.63", "172.16.15.63"},
+		{"0.201.1.1", "pod (201) is outside its allowed range 0-200"},
+		{"0.255.1.1", "pod (255) is outside its allowed range 0-200"},
+		{"0.1.0.1", "rack (0) is outside its allowed range 1-60"},
+		{"0.1.61.1", "rack (61) is outside its allowed range 1-60"},
+		// the width still limits the value
+		{"0.256.1.1", "256"},
+	}
+
+	for _, tt := range tests {
+		got, err := translate(tt.value, mask, "172.16.0.0", translateOptions{})
+		if err != nil {
+			got = err.Error()
+		}
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("translate(%q, %q) = %q, want %q", tt.value, mask, got, tt.want)
+		}
+	}
+}
+
+func TestParseFieldRanges(t *testing.T) {
+	ranges, err := parseFieldRanges("8:a[3-7],8,8:c,8[0-9]")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []fieldRange{{true, 3, 7}, {}, {}, {true, 0, 9}}
+	if len(ranges) != len(want) {
+		t.Fatalf("parseFieldRanges = %v, want %v", ranges, want)
+	}
+	for i := range want {
+		if ranges[i] != want[i] {
+			t.Errorf("range %d = %v, want %v", i, ranges[i], want[i])
+		}
+	}
+
+	if ranges, err := parseFieldRanges("8.8.8.8"); err != nil || ranges != nil {
+		t.Errorf("an unnamed mask has ranges %v, %v", ranges, err)
+	}
+	if _, err := parseFieldRanges("8:pod[9-3],24"); err == nil || !strings.Contains(err.Error(), "ends before it starts") {
+		t.Errorf("a backwards range: %v", err)
+	}
+	if _, err := translate("0.1.1.1", "12:region,8:pod[9-3],6,6", "0.0.0.0", translateOptions{}); err == nil {
+		t.Errorf("translate accepted a backwards range")
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// maskAlignCmd represents the mask-align command
+var maskAlignCmd = &cobra.Command{
+	Use:   "mask-align <mask>",
+	Short: "snap a mask's fields to octet boundaries",
+	Long: `Print the nearest octet aligned equivalent of a mask, for tools which
+only understand dotted netmasks.  Each boundary between fields moves to
+the nearest multiple of 8 bits (halfway rounds up), and fields left with
+no width are dropped.  A second line reports whether the alignment
+changed the mask's meaning.  Example:
+
+	cidr mask-align 12.8.6.6
+
+returns
+
+	16.8.8
+	changed: boundary 12 -> 16, boundary 20 -> 24, boundary 26 -> 24 (a field was dropped)
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		aligned, moves, err := maskAlign(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		fields, _ := parseMask(args[0])
+		n := len(fields) - len(aligned)
+		if len(aligned) == 1 {
+			// parse needs at least two fields
+			aligned = append([]int{0}, aligned...)
+		}
+
+		fmt.Printf("%s\n", formatMask(aligned))
+		if resultOnly {
+			return
+		}
+		if len(moves) == 0 {
+			fmt.Printf("unchanged\n")
+			return
+		}
+		dropped := ""
+		if n == 1 {
+			dropped = " (a field was dropped)"
+		} else if n > 1 {
+			dropped = fmt.Sprintf(" (%d fields were dropped)", n)
+		}
+		fmt.Printf("changed: %s%s\n", strings.Join(moves, ", "), dropped)
+	},
+}
+
+// move each field boundary to the nearest octet boundary, returning the
+// aligned widths and a description of each boundary which moved
+func maskAlign(mask string) ([]int, []string, error) {
+	fields, err := parseMask(mask)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var aligned []int
+	var moves []string
+	at, prev := 0, 0
+	for _, f := range fields {
+		at += f
+		snapped := (at + 4) / 8 * 8
+		if snapped != at {
+			moves = append(moves, fmt.Sprintf("boundary %d -> %d", at, snapped))
+		}
+		if snapped > prev {
+			aligned = append(aligned, snapped-prev)
+			prev = snapped
+		}
+	}
+
+	return aligned, moves, nil
+}
+
+func init() {
+	RootCmd.AddCommand(maskAlignCmd)
+}
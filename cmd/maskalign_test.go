@@ -0,0 +1,55 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMaskAlign(t *testing.T) {
+	tests := []struct {
+		mask, aligned, moves string
+	}{
+		{"12.8.6.6", "16.8.8", "boundary 12 -> 16, boundary 20 -> 24, boundary 26 -> 24"},
+		{"8.8.8.8", "8.8.8.8", ""},
+		{"16.8.8", "16.8.8", ""},
+		{"10.6.16", "8.8.16", "boundary 10 -> 8"},
+		{"4.28", "8.24", "boundary 4 -> 8"},
+	}
+
+	for _, tt := range tests {
+		aligned, moves, err := maskAlign(tt.mask)
+		if err != nil {
+			t.Errorf("maskAlign(%s): %s", tt.mask, err)
+			continue
+		}
+		if got := formatMask(aligned); got != tt.aligned {
+			t.Errorf("maskAlign(%s) = %s, want %s", tt.mask, got, tt.aligned)
+		}
+		if got := strings.Join(moves, ", "); got != tt.moves {
+			t.Errorf("maskAlign(%s) moved %q, want %q", tt.mask, got, tt.moves)
+		}
+	}
+}
+
+func TestMaskAlignCommand(t *testing.T) {
+	want := "16.8.8\nchanged: boundary 12 -> 16, boundary 20 -> 24, boundary 26 -> 24 (a field was dropped)\n"
+	if got := runRoot(t, "mask-align", "12.8.6.6"); got != want {
+		t.Errorf("mask-align 12.8.6.6 printed %q, want %q", got, want)
+	}
+	if got := runRoot(t, "mask-align", "8.8.16"); got != "8.8.16\nunchanged\n" {
+		t.Errorf("mask-align 8.8.16 printed %q", got)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// maskBitsCmd represents the mask-bits command
+var maskBitsCmd = &cobra.Command{
+	Use:   "mask-bits",
+	Short: "report how many of a mask's bits are network and host bits",
+	Long: `Split a mask's bits into network bits, fixed by the within, and
+host bits, left for the value to fill in.  The within fixes the bits of
+its prefix if given in CIDR notation, or otherwise every octet up to its
+last non-zero one (see prefix-of).  A mask field which the within fixes
+even partially counts as network.  Example:
+
+	cidr mask-bits --mask 12.8.6.6 --within 172.16.0.0/12
+
+returns
+
+	12 network, 20 host
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		mask, err := cmd.Flags().GetString("mask")
+		if err != nil {
+			panic(err)
+		}
+		within, err := cmd.Flags().GetString("within")
+		if err != nil {
+			panic(err)
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+
+		split, err := maskBits(mask, within)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		if output == "text" && resultOnly {
+			fmt.Printf("%d %d\n", split.Network, split.Host)
+			return
+		}
+		if output == "text" {
+			fmt.Printf("%d network, %d host\n", split.Network, split.Host)
+			return
+		}
+		if err := writeEncoded(os.Stdout, split, output); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// the network and host bits of a mask
+type bitSplit struct {
+	Network  int    `json:"network" yaml:"network"`
+	Host     int    `json:"host" yaml:"host"`
+	Hostmask string `json:"hostmask" yaml:"hostmask"`
+}
+
+// split the mask's bits, rounding the within's prefix up to a field boundary
+func maskBits(mask, within string) (bitSplit, error) {
+	fields, err := parseMask(mask)
+	if err != nil {
+		return bitSplit{}, err
+	}
+
+	fixed, err := withinPrefix(within, nil)
+	if err != nil {
+		return bitSplit{}, err
+	}
+
+	network := 0
+	for _, f := range fields {
+		if network >= fixed {
+			break
+		}
+		network += f
+	}
+
+	return bitSplit{
+		Network:  network,
+		Host:     32 - network,
+		Hostmask: formatIPv4(^prefixMask(network)),
+	}, nil
+}
+
+func init() {
+	RootCmd.AddCommand(maskBitsCmd)
+
+	maskBitsCmd.Flags().StringP("mask", "m", "8:13:4:7", "bitmask to split")
+	maskBitsCmd.Flags().StringP("within", "w", "0.0.0.0", "the within, optionally with a /prefix")
+	maskBitsCmd.Flags().StringP("output", "o", "text", "output format: text, json or yaml")
+}
@@ -0,0 +1,72 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestMaskBits(t *testing.T) {
+	tests := []struct {
+		mask, within  string
+		network, host int
+		hostmask      string
+	}{
+		{"12.8.6.6", "172.16.0.0/12", 12, 20, "0.15.255.255"},
+		{"12.8.6.6", "0.0.0.0/12", 12, 20, "0.15.255.255"},
+		// a within's prefix is rounded up to the next field boundary
+		{"12.8.6.6", "172.16.0.0/14", 20, 12, "0.0.15.255"},
+		// without a prefix, the last non-zero octet sets it
+		{"12.8.6.6", "172.16.0.0", 20, 12, "0.0.15.255"},
+		{"8.8.8.8", "10.0.0.0", 8, 24, "0.255.255.255"},
+		{"8.8.8.8", "0.0.0.0", 0, 32, "255.255.255.255"},
+	}
+
+	for _, tt := range tests {
+		got, err := maskBits(tt.mask, tt.within)
+		if err != nil {
+			t.Errorf("maskBits(%q, %q): %s", tt.mask, tt.within, err)
+			continue
+		}
+		if got.Network != tt.network || got.Host != tt.host || got.Hostmask != tt.hostmask {
+			t.Errorf("maskBits(%q, %q) = %d network, %d host, %s, want %d, %d, %s",
+				tt.mask, tt.within, got.Network, got.Host, got.Hostmask, tt.network, tt.host, tt.hostmask)
+		}
+	}
+}
+
+// prefix-of, mask-bits and hostmask share one rule for the bits a
+// within fixes
+func TestWithinPrefixShared(t *testing.T) {
+	for _, within := range []string{"172.16.0.0", "172.16.0.0/12", "10.0.0.0", "10.1.0.0/24", "0.0.0.0"} {
+		fixed, err := withinPrefix(within, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		prefix, err := prefixOf(within, "8.8.8.8")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if prefix != fixed {
+			t.Errorf("prefix-of %s gives /%d, withinPrefix /%d", within, prefix, fixed)
+		}
+
+		hm, err := hostmask("1.31", within)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := formatIPv4(^prefixMask(fixed)); hm.Wildcard != want {
+			t.Errorf("hostmask %s has the wildcard %s, want %s", within, hm.Wildcard, want)
+		}
+	}
+}
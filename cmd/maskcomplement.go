@@ -0,0 +1,84 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// maskComplementCmd represents the mask-complement command
+var maskComplementCmd = &cobra.Command{
+	Use:   "mask-complement <mask>",
+	Short: "mirror a mask's fields across the address",
+	Long: `Print the complement of a mask: the same fields in reverse order, so
+that a field boundary b bits from the top of the address becomes one b
+bits from the bottom.  The widths still sum to 32, and the leading
+(network) field of the mask becomes the trailing (host) field of its
+complement.  Named fields keep their names.  Example:
+
+	cidr mask-complement 12.8.6.6
+
+returns
+
+	6.6.8.12
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		str, err := maskComplement(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s\n", str)
+	},
+}
+
+// reverse the mask's fields, keeping any names with their widths
+func maskComplement(mask string) (string, error) {
+	fields, names, err := parseNamedMask(mask)
+	if err != nil {
+		return "", err
+	}
+
+	n := len(fields)
+	reversed := make([]int, n)
+	for i, f := range fields {
+		reversed[n-i-1] = f
+	}
+	if !isNamedMask(stripMaskComment(mask)) {
+		return formatMask(reversed), nil
+	}
+
+	parts := make([]string, n)
+	for i := range fields {
+		parts[n-i-1] = fmt.Sprintf("%d", fields[i])
+		if len(names[i]) > 0 {
+			parts[n-i-1] += ":" + names[i]
+		}
+	}
+	return strings.Join(parts, ","), nil
+}
+
+func init() {
+	RootCmd.AddCommand(maskComplementCmd)
+}
@@ -0,0 +1,57 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestMaskComplement(t *testing.T) {
+	tests := []struct {
+		mask, want string
+	}{
+		{"12.8.6.6", "6.6.8.12"},
+		{"8.8.8.8", "8.8.8.8"},
+		{"31.1", "1.31"},
+		{"12:region,8:pod,6:rack,6:host", "6:host,6:rack,8:pod,12:region"},
+		{"16:net,16", "16,16:net"},
+		{"12.8.6.6 # regional", "6.6.8.12"},
+	}
+
+	for _, tt := range tests {
+		got, err := maskComplement(tt.mask)
+		if err != nil {
+			t.Errorf("maskComplement(%q): %s", tt.mask, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("maskComplement(%q) = %s, want %s", tt.mask, got, tt.want)
+		}
+
+		// the complement is still a valid mask, and its complement is
+		// the original
+		if _, err := validateMask(got); err != nil {
+			t.Errorf("the complement %s is invalid -- %s", got, err)
+		}
+		back, err := maskComplement(got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if back != stripMaskComment(tt.mask) {
+			t.Errorf("the complement of %s is %s, want %s", got, back, stripMaskComment(tt.mask))
+		}
+	}
+
+	if got, err := maskComplement("8.8.x.8"); err == nil {
+		t.Errorf("maskComplement(8.8.x.8) = %s, expected an error", got)
+	}
+}
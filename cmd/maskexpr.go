@@ -0,0 +1,171 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A mask expression gives each field's width as arithmetic, for widths
+// worked out by hand.  Fields are separated by '+' at the top level, and
+// each is an expression of integers, '*', '-', and parentheses, inside
+// which '+' adds.  For example
+//
+//	3*8+6+2      is the mask 24.6.2
+//	(2*8+4)+6+6  is the mask 20.6.6
+//
+// A mask is read as an expression when it contains '*', '(' or '+' and
+// nothing but digits, those operators and spaces.  8+8+8+8 means the
+// same either way.
+
+// true if the mask should be read as an expression
+func isMaskExpression(mask string) bool {
+	if !strings.ContainsAny(mask, "*(+") {
+		return false
+	}
+	for _, c := range mask {
+		if !strings.ContainsRune("0123456789+-*() ", c) {
+			return false
+		}
+	}
+	return true
+}
+
+// the largest magnitude of a number or product in a mask expression,
+// far beyond any field width, so the arithmetic can't overflow
+const maxMaskExprValue = 1 << 16
+
+// a recursive descent evaluator over a mask expression
+type maskExpr struct {
+	input string
+	pos   int
+}
+
+// evaluate the mask expression into its field widths
+func parseMaskExpression(mask string) ([]int, error) {
+	e := &maskExpr{input: strings.ReplaceAll(mask, " ", "")}
+
+	var fields []int
+	for {
+		v, err := e.difference()
+		if err != nil {
+			return nil, err
+		}
+		if v < 0 {
+			return nil, e.errorf("field #%d has a negative width (%d)", len(fields), v)
+		}
+		fields = append(fields, v)
+
+		if e.pos == len(e.input) {
+			break
+		}
+		if e.input[e.pos] != '+' {
+			return nil, e.errorf("unexpected '%c'", e.input[e.pos])
+		}
+		e.pos++
+	}
+
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("The mask '%s' has only one or no fields", mask)
+	}
+	logger.Debug("mask expression", "input", mask, "fields", fields)
+	return fields, nil
+}
+
+func (e *maskExpr) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("error parsing mask expression '%s' at position %d -- %s",
+		e.input, e.pos+1, fmt.Sprintf(format, args...))
+}
+
+// sum := difference ('+' difference)*, used inside parentheses
+func (e *maskExpr) sum() (int, error) {
+	v, err := e.difference()
+	for err == nil && e.pos < len(e.input) && e.input[e.pos] == '+' {
+		e.pos++
+		var w int
+		w, err = e.difference()
+		v += w
+	}
+	return v, err
+}
+
+// difference := product ('-' product)*
+func (e *maskExpr) difference() (int, error) {
+	v, err := e.product()
+	for err == nil && e.pos < len(e.input) && e.input[e.pos] == '-' {
+		e.pos++
+		var w int
+		w, err = e.product()
+		v -= w
+	}
+	return v, err
+}
+
+// product := factor ('*' factor)*
+func (e *maskExpr) product() (int, error) {
+	v, err := e.factor()
+	for err == nil && e.pos < len(e.input) && e.input[e.pos] == '*' {
+		e.pos++
+		var w int
+		w, err = e.factor()
+		if err == nil && w != 0 && magnitude(v) > maxMaskExprValue/magnitude(w) {
+			return 0, e.errorf("product too large")
+		}
+		v *= w
+	}
+	return v, err
+}
+
+// factor := integer | '(' sum ')'
+func (e *maskExpr) factor() (int, error) {
+	if e.pos == len(e.input) {
+		return 0, e.errorf("unexpected end of expression")
+	}
+
+	if e.input[e.pos] == '(' {
+		e.pos++
+		v, err := e.sum()
+		if err != nil {
+			return 0, err
+		}
+		if e.pos == len(e.input) || e.input[e.pos] != ')' {
+			return 0, e.errorf("missing ')'")
+		}
+		e.pos++
+		return v, nil
+	}
+
+	start := e.pos
+	v := 0
+	for e.pos < len(e.input) && e.input[e.pos] >= '0' && e.input[e.pos] <= '9' {
+		v = v*10 + int(e.input[e.pos]-'0')
+		if v > maxMaskExprValue {
+			return 0, e.errorf("number too large")
+		}
+		e.pos++
+	}
+	if e.pos == start {
+		return 0, e.errorf("expected a number, found '%c'", e.input[e.pos])
+	}
+	return v, nil
+}
+
+// the absolute value of v
+func magnitude(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
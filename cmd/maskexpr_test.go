@@ -0,0 +1,84 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMaskExpression(t *testing.T) {
+	tests := []struct {
+		mask, want string
+	}{
+		{"3*8+6+2", "24.6.2"},
+		{"(2*8+4)+6+6", "20.6.6"},
+		{"8+8+8+8", "8.8.8.8"},
+		{"2*(4+2)+20", "12.20"},
+		{"16 - 4 + 8 + 6 + 6", "12.8.6.6"},
+		{"4*2*2+16", "16.16"},
+		{"256*256*0+32", "0.32"},
+	}
+
+	for _, tt := range tests {
+		if !isMaskExpression(tt.mask) {
+			t.Errorf("%q wasn't read as an expression", tt.mask)
+			continue
+		}
+		fields, err := parseMaskExpression(tt.mask)
+		if err != nil {
+			t.Errorf("parseMaskExpression(%q): %s", tt.mask, err)
+			continue
+		}
+		if got := formatMask(fields); got != tt.want {
+			t.Errorf("parseMaskExpression(%q) = %s, want %s", tt.mask, got, tt.want)
+		}
+	}
+
+	got, err := translate("0.1.1", "3*8+6+2", "10.0.0.0", translateOptions{})
+	if err != nil || got != "10.0.0.5" {
+		t.Errorf("translate with the mask 3*8+6+2 = %q, %v, want 10.0.0.5", got, err)
+	}
+}
+
+func TestNotMaskExpression(t *testing.T) {
+	for _, mask := range []string{"12.8.6.6", "8:8:8:8", "12:region,8:pod,6:rack,6:host", "16-16"} {
+		if isMaskExpression(mask) {
+			t.Errorf("%q was read as an expression", mask)
+		}
+	}
+}
+
+func TestMaskExpressionErrors(t *testing.T) {
+	tests := []struct {
+		mask, want string
+	}{
+		{"3*8", "only one or no fields"},
+		{"8+", "at position 3 -- unexpected end of expression"},
+		{"(8+8+16", "missing ')'"},
+		{"8+*8", "expected a number, found '*'"},
+		{"8)+24", "unexpected ')'"},
+		{"4-8+28", "field #0 has a negative width (-4)"},
+		{"99999+1", "number too large"},
+		{"65536*65536*65536*65536+1", "product too large"},
+		{"(0-256)*256*2+1", "product too large"},
+	}
+
+	for _, tt := range tests {
+		_, err := parseMaskExpression(tt.mask)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("parseMaskExpression(%q) = %v, want %q", tt.mask, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// mergeAdjacentCmd represents the merge-adjacent command
+var mergeAdjacentCmd = &cobra.Command{
+	Use:   "merge-adjacent [cidr...]",
+	Short: "merge only exactly adjacent, aligned pairs of CIDRs",
+	Long: `Merge pairs of CIDRs which are the two halves of a larger block, e.g.
+two /25s into a /24, repeating until no pair remains.  Unlike summarize,
+blocks which overlap or are contained in another are left as they are,
+and ranges are not accepted.  The CIDRs are read from stdin, one per
+line, when none are given.  Example:
+
+	cidr merge-adjacent 10.0.0.0/25 10.0.0.128/25 10.0.1.0/24 10.0.3.0/25
+
+returns
+
+	10.0.0.0/23
+	10.0.3.0/25
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+
+		var blocks []block
+		if len(args) > 0 {
+			for _, arg := range args {
+				b, err := parseBlock(arg)
+				if err != nil {
+					fmt.Printf("%s\n", err)
+					os.Exit(1)
+				}
+				blocks = append(blocks, b)
+			}
+		} else {
+			words, err := splitFlag(cmd)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+			blocks, err = readBlocks(os.Stdin, words)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+		}
+
+		if err := writeBlocks(os.Stdout, mergeAdjacent(blocks), output); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// the other half of the block's parent
+func (b block) sibling() block {
+	return block{base: b.base ^ uint32(b.size()), prefix: b.prefix}
+}
+
+// repeatedly replace pairs of sibling blocks with their parent, from the
+// longest prefix up, and return the sorted, de-duplicated result
+func mergeAdjacent(blocks []block) []block {
+	set := make(map[block]bool, len(blocks))
+	for _, b := range blocks {
+		set[b] = true
+	}
+
+	for prefix := 32; prefix > 0; prefix-- {
+		for b := range set {
+			if b.prefix != prefix || !set[b] {
+				continue
+			}
+			s := b.sibling()
+			if !set[s] {
+				continue
+			}
+			delete(set, b)
+			delete(set, s)
+			set[block{base: b.base &^ uint32(b.size()), prefix: prefix - 1}] = true
+		}
+	}
+
+	result := make([]block, 0, len(set))
+	for b := range set {
+		result = append(result, b)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].base != result[j].base {
+			return result[i].base < result[j].base
+		}
+		return result[i].prefix < result[j].prefix
+	})
+	return result
+}
+
+func init() {
+	RootCmd.AddCommand(mergeAdjacentCmd)
+
+	mergeAdjacentCmd.Flags().StringP("output", "o", "text", "output format: text, json or yaml")
+	addSplitFlag(mergeAdjacentCmd)
+}
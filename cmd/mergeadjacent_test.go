@@ -0,0 +1,84 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// parse the CIDRs, failing the test on an error
+func parseBlocks(t *testing.T, cidrs ...string) []block {
+	t.Helper()
+	blocks := make([]block, len(cidrs))
+	for i, c := range cidrs {
+		b, err := parseBlock(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blocks[i] = b
+	}
+	return blocks
+}
+
+func blockStrings(blocks []block) string {
+	s := make([]string, len(blocks))
+	for i, b := range blocks {
+		s[i] = b.String()
+	}
+	return strings.Join(s, " ")
+}
+
+func TestMergeAdjacent(t *testing.T) {
+	tests := []struct {
+		cidrs     []string
+		merged    string
+		summarize string
+	}{
+		// two halves merge, and then merge again
+		{[]string{"10.0.0.0/25", "10.0.0.128/25", "10.0.1.0/24", "10.0.3.0/25"},
+			"10.0.0.0/23 10.0.3.0/25", "10.0.0.0/23 10.0.3.0/25"},
+		// adjacent but not aligned, so neither merges them
+		{[]string{"10.0.1.0/24", "10.0.2.0/24"},
+			"10.0.1.0/24 10.0.2.0/24", "10.0.1.0/24 10.0.2.0/24"},
+		// a block inside another is left alone
+		{[]string{"10.0.0.0/24", "10.0.0.0/25"},
+			"10.0.0.0/24 10.0.0.0/25", "10.0.0.0/24"},
+		// unequal neighbours aren't siblings
+		{[]string{"10.0.0.0/25", "10.0.0.128/26", "10.0.0.192/26"},
+			"10.0.0.0/24", "10.0.0.0/24"},
+		{[]string{"10.0.0.0/25", "10.0.0.128/26"},
+			"10.0.0.0/25 10.0.0.128/26", "10.0.0.0/25 10.0.0.128/26"},
+		// summarize absorbs the nested block into the merge
+		{[]string{"10.0.0.0/23", "10.0.1.0/24", "10.0.2.0/23"},
+			"10.0.0.0/22 10.0.1.0/24", "10.0.0.0/22"},
+		// duplicates collapse
+		{[]string{"10.0.0.0/24", "10.0.0.0/24"}, "10.0.0.0/24", "10.0.0.0/24"},
+	}
+
+	for _, tt := range tests {
+		blocks := parseBlocks(t, tt.cidrs...)
+		if got := blockStrings(mergeAdjacent(blocks)); got != tt.merged {
+			t.Errorf("mergeAdjacent(%v) = %s, want %s", tt.cidrs, got, tt.merged)
+		}
+
+		ranges := make([]ipRange, len(blocks))
+		for i, b := range blocks {
+			ranges[i] = b.span()
+		}
+		if got := blockStrings(summarize(ranges)); got != tt.summarize {
+			t.Errorf("summarize(%v) = %s, want %s", tt.cidrs, got, tt.summarize)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"iter"
+	"net/netip"
+)
+
+// TranslateAddr packs the value into the mask's fields, ORs in the within,
+// and returns the result as a netip.Addr.
+func TranslateAddr(value, mask, within string) (netip.Addr, error) {
+	return translateAddr(value, mask, within, translateOptions{})
+}
+
+// TranslatePrefix is TranslateAddr, returning the address together with
+// the given prefix length.  The host bits of the address are preserved.
+func TranslatePrefix(value, mask, within string, bits int) (netip.Prefix, error) {
+	return translatePrefix(value, mask, within, bits, translateOptions{})
+}
+
+func translateAddr(value, mask, within string, opts translateOptions) (netip.Addr, error) {
+	if opts.family == 6 {
+		return pack6(value, mask, within, opts)
+	}
+
+	octets, err := pack(value, mask, within, opts)
+	if err != nil {
+		return netip.Addr{}, err
+	}
+
+	var addr [4]byte
+	for i, o := range octets {
+		addr[i] = byte(o)
+	}
+
+	return netip.AddrFrom4(addr), nil
+}
+
+func translatePrefix(value, mask, within string, bits int, opts translateOptions) (netip.Prefix, error) {
+	addr, err := translateAddr(value, mask, within, opts)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	// a prefix can't carry a zone
+	if len(addr.Zone()) > 0 {
+		logger.Warn("dropping the zone from the prefix", "zone", addr.Zone())
+		addr = addr.WithZone("")
+	}
+
+	prefix := netip.PrefixFrom(addr, bits)
+	if !prefix.IsValid() {
+		return netip.Prefix{}, fmt.Errorf("invalid prefix length %d, expected 0-%d", bits, addr.BitLen())
+	}
+
+	return prefix, nil
+}
+
+// the 32 bit value of an IPv4 netip.Addr
+func addrToIPv4(addr netip.Addr) uint32 {
+	a := addr.As4()
+	return uint32(a[0])<<24 | uint32(a[1])<<16 | uint32(a[2])<<8 | uint32(a[3])
+}
+
+// a 32 bit value as a netip.Addr
+func ipv4ToAddr(addr uint32) netip.Addr {
+	return netip.AddrFrom4([4]byte{byte(addr >> 24), byte(addr >> 16), byte(addr >> 8), byte(addr)})
+}
+
+// the block as a netip.Prefix
+func (b block) netipPrefix() netip.Prefix {
+	return netip.PrefixFrom(ipv4ToAddr(b.base), b.prefix)
+}
+
+// convert an IPv4 netip.Prefix into a block, discarding host bits
+func blockFromPrefix(p netip.Prefix) (block, error) {
+	if !p.IsValid() || !p.Addr().Is4() {
+		return block{}, fmt.Errorf("'%s' is not an IPv4 prefix", p)
+	}
+	return block{base: addrToIPv4(p.Addr()) & prefixMask(p.Bits()), prefix: p.Bits()}, nil
+}
+
+// Iterate returns the addresses of the prefix in ascending order, from
+// its network address to its last.  Addresses are generated as they are
+// ranged over, so even a large IPv6 prefix may be consumed partially.
+func Iterate(prefix netip.Prefix) iter.Seq[netip.Addr] {
+	prefix = prefix.Masked()
+	return iterateFrom(prefix.Addr(), prefix)
+}
+
+// the addresses of the prefix, starting at first
+func iterateFrom(first netip.Addr, prefix netip.Prefix) iter.Seq[netip.Addr] {
+	return func(yield func(netip.Addr) bool) {
+		for addr := first; addr.IsValid() && prefix.Contains(addr); addr = addr.Next() {
+			if !yield(addr) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestTranslatePrefixRoundTrip(t *testing.T) {
+	tests := []struct {
+		value, mask, within string
+		bits                int
+		want                string
+	}{
+		{"1.1.1", "16.8.8", "172.16.0.0", 24, "172.17.1.1/24"},
+		{"0.0.0.0", "8.8.8.8", "10.0.0.0", 8, "10.0.0.0/8"},
+		{"1.2.3.4", "8.8.8.8", "0.0.0.0", 32, "1.2.3.4/32"},
+		{"1.2.3.4", "8.8.8.8", "0.0.0.0", 0, "1.2.3.4/0"},
+	}
+
+	for _, tt := range tests {
+		prefix, err := TranslatePrefix(tt.value, tt.mask, tt.within, tt.bits)
+		if err != nil {
+			t.Errorf("TranslatePrefix(%q, %q, %q, %d): %s", tt.value, tt.mask, tt.within, tt.bits, err)
+			continue
+		}
+
+		parsed, err := netip.ParsePrefix(prefix.String())
+		if err != nil {
+			t.Errorf("netip.ParsePrefix(%q): %s", prefix, err)
+			continue
+		}
+		if parsed != prefix || parsed.String() != tt.want {
+			t.Errorf("%s round-trips to %s, want %s", prefix, parsed, tt.want)
+		}
+
+		addr, err := TranslateAddr(tt.value, tt.mask, tt.within)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if addr != parsed.Addr() {
+			t.Errorf("TranslateAddr gave %s, but the prefix holds %s", addr, parsed.Addr())
+		}
+	}
+}
+
+func TestTranslatePrefixInvalidLength(t *testing.T) {
+	if _, err := TranslatePrefix("1.2.3.4", "8.8.8.8", "0.0.0.0", 33); err == nil {
+		t.Error("expected a /33 to be rejected")
+	}
+}
+
+func TestIterateFully(t *testing.T) {
+	var got []string
+	for addr := range Iterate(netip.MustParsePrefix("10.0.0.4/30")) {
+		got = append(got, addr.String())
+	}
+
+	want := []string{"10.0.0.4", "10.0.0.5", "10.0.0.6", "10.0.0.7"}
+	if len(got) != len(want) {
+		t.Fatalf("iterated %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("address #%d is %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestIterateMasksHostBits(t *testing.T) {
+	n := 0
+	for addr := range Iterate(netip.MustParsePrefix("10.0.0.6/30")) {
+		if n == 0 && addr.String() != "10.0.0.4" {
+			t.Errorf("started at %s, want the network address 10.0.0.4", addr)
+		}
+		n++
+	}
+	if n != 4 {
+		t.Errorf("iterated %d addresses, want 4", n)
+	}
+}
+
+func TestIteratePartially(t *testing.T) {
+	// breaking out of the loop stops the iteration, even over a /8
+	var got []string
+	for addr := range Iterate(netip.MustParsePrefix("10.0.0.0/8")) {
+		if len(got) == 3 {
+			break
+		}
+		got = append(got, addr.String())
+	}
+	if len(got) != 3 || got[2] != "10.0.0.2" {
+		t.Errorf("iterated %v, want the first 3 addresses", got)
+	}
+}
+
+func TestIterateFrom(t *testing.T) {
+	prefix := netip.MustParsePrefix("2001:db8::/126")
+
+	var got []string
+	for addr := range iterateFrom(netip.MustParseAddr("2001:db8::2"), prefix) {
+		got = append(got, addr.String())
+	}
+	if len(got) != 2 || got[0] != "2001:db8::2" || got[1] != "2001:db8::3" {
+		t.Errorf("iterated %v, want 2001:db8::2 and 2001:db8::3", got)
+	}
+
+	// starting outside the prefix yields nothing
+	for addr := range iterateFrom(netip.MustParseAddr("2001:db8::4"), prefix) {
+		t.Errorf("iterated %s, outside of %s", addr, prefix)
+	}
+}
+
+func TestIterateWholeSpace(t *testing.T) {
+	// the last address of the space must not wrap around to the first
+	n := 0
+	for range iterateFrom(netip.MustParseAddr("255.255.255.254"), netip.MustParsePrefix("0.0.0.0/0")) {
+		n++
+	}
+	if n != 2 {
+		t.Errorf("iterated %d addresses, want 2", n)
+	}
+}
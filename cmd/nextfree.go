@@ -0,0 +1,202 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// nextFreeCmd represents the next-free command
+var nextFreeCmd = &cobra.Command{
+	Use:   "next-free",
+	Short: "allocate the next free block, recording it in a state file",
+	Long: `Read the allocations recorded in the --state file, allocate the lowest
+free block of the --prefix length within the supernet, record it in the
+file and print it.  The file is created when it doesn't exist, and is
+locked (with a <state>.lock file) while it is updated so concurrent runs
+don't hand out the same block.  Example:
+
+	cidr next-free --within 10.0.0.0/16 --prefix 24 --state allocs.json
+
+returns
+
+	10.0.0.0/24
+
+and 10.0.1.0/24 the next time it is run.
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		within, err := cmd.Flags().GetString("within")
+		if err != nil {
+			panic(err)
+		}
+		prefix, err := cmd.Flags().GetString("prefix")
+		if err != nil {
+			panic(err)
+		}
+		state, err := cmd.Flags().GetString("state")
+		if err != nil {
+			panic(err)
+		}
+
+		bits, err := parsePrefixLen(prefix)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		p, err := nextFree(state, within, bits)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s\n", p)
+	},
+}
+
+// the contents of a next-free state file
+type allocState struct {
+	Within      string   `json:"within"`
+	Allocations []string `json:"allocations"`
+}
+
+// how long to wait for another run to release the state file's lock
+const lockTimeout = 10 * time.Second
+
+// take the lock on the state file, returning a function to release it
+func lockState(path string) (func(), error) {
+	lock := path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lock) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("error locking %s -- %s", path, err)
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for the lock %s; remove it if no other run is active", lock)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// allocate the next free block of the prefix length in the state file
+func nextFree(path, within string, prefix int) (netip.Prefix, error) {
+	unlock, err := lockState(path)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	defer unlock()
+
+	var state allocState
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &state); err != nil {
+			return netip.Prefix{}, fmt.Errorf("error parsing state file %s -- %s", path, err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return netip.Prefix{}, fmt.Errorf("error reading state file -- %s", err)
+	}
+
+	switch {
+	case len(state.Within) == 0 && len(within) == 0:
+		return netip.Prefix{}, fmt.Errorf("--within is required for a new state file")
+	case len(state.Within) == 0:
+		state.Within = within
+	case len(within) > 0 && within != state.Within:
+		return netip.Prefix{}, fmt.Errorf("the state file %s allocates from %s, not %s", path, state.Within, within)
+	}
+
+	supernet, err := netip.ParsePrefix(state.Within)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("'%s' is not a valid CIDR", state.Within)
+	}
+	a, err := NewAllocator(supernet)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	for _, s := range state.Allocations {
+		p, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("the state file %s has the invalid allocation '%s'", path, s)
+		}
+		if err := a.Reserve(p); err != nil {
+			return netip.Prefix{}, fmt.Errorf("the state file %s is inconsistent -- %s", path, err)
+		}
+	}
+
+	p, err := a.Allocate(prefix)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	state.Allocations = state.Allocations[:0]
+	for _, x := range a.Allocated() {
+		state.Allocations = append(state.Allocations, x.String())
+	}
+	if err := writeState(path, state); err != nil {
+		return netip.Prefix{}, err
+	}
+	return p, nil
+}
+
+// replace the state file, via a temporary file so it is never left
+// half written
+func writeState(path string, state allocState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("error writing state file -- %s", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(append(data, '\n'))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing state file -- %s", err)
+	}
+	return nil
+}
+
+func init() {
+	RootCmd.AddCommand(nextFreeCmd)
+
+	nextFreeCmd.Flags().StringP("within", "w", "", "the supernet to allocate from (required for a new state file)")
+	nextFreeCmd.Flags().StringP("prefix", "p", "", "the prefix length to allocate, e.g. 24 or /24")
+	nextFreeCmd.Flags().String("state", "", "the JSON file recording the allocations")
+	nextFreeCmd.MarkFlagRequired("prefix")
+	nextFreeCmd.MarkFlagRequired("state")
+}
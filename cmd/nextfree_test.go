@@ -0,0 +1,132 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNextFreeSequential(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "allocs.json")
+
+	tests := []struct {
+		within string
+		prefix int
+		want   string
+	}{
+		{"10.0.0.0/16", 24, "10.0.0.0/24"},
+		{"", 24, "10.0.1.0/24"},
+		{"", 23, "10.0.2.0/23"},
+		{"10.0.0.0/16", 24, "10.0.4.0/24"},
+		{"", 25, "10.0.5.0/25"},
+		{"", 26, "10.0.5.128/26"},
+	}
+	for _, tt := range tests {
+		p, err := nextFree(path, tt.within, tt.prefix)
+		if err != nil {
+			t.Fatalf("next-free /%d: %s", tt.prefix, err)
+		}
+		if p.String() != tt.want {
+			t.Errorf("next-free /%d = %s, want %s", tt.prefix, p, tt.want)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var state allocState
+	if err := json.Unmarshal(data, &state); err != nil {
+		t.Fatal(err)
+	}
+	if state.Within != "10.0.0.0/16" || len(state.Allocations) != len(tests) {
+		t.Errorf("the state file records %v", state)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("the lock was left behind: %v", err)
+	}
+}
+
+func TestNextFreeCommand(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "allocs.json")
+	for _, want := range []string{"10.0.0.0/24", "10.0.1.0/24", "10.0.2.0/24"} {
+		got := runRoot(t, "next-free", "--within", "10.0.0.0/16", "--prefix", "/24", "--state", path)
+		if got != want+"\n" {
+			t.Errorf("next-free printed %q, want %s", got, want)
+		}
+	}
+}
+
+func TestNextFreeErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "allocs.json")
+
+	if _, err := nextFree(path, "", 24); err == nil || !strings.Contains(err.Error(), "--within is required") {
+		t.Errorf("a new state file without --within: %v", err)
+	}
+	if _, err := nextFree(path, "10.0.0.0/24", 24); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := nextFree(path, "10.1.0.0/24", 24); err == nil || !strings.Contains(err.Error(), "allocates from 10.0.0.0/24") {
+		t.Errorf("a different --within: %v", err)
+	}
+	if _, err := nextFree(path, "", 24); err == nil {
+		t.Errorf("allocated from a full supernet")
+	}
+
+	bad := filepath.Join(dir, "bad.json")
+	if err := os.WriteFile(bad, []byte(`{"within": "10.0.0.0/16", "allocations": ["10.0.0.0/24", "10.0.0.128/25"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := nextFree(bad, "", 24); err == nil || !strings.Contains(err.Error(), "inconsistent") {
+		t.Errorf("overlapping allocations in the state file: %v", err)
+	}
+}
+
+func TestNextFreeConcurrent(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "allocs.json")
+	if _, err := nextFree(path, "10.0.0.0/16", 24); err != nil {
+		t.Fatal(err)
+	}
+
+	const runs = 8
+	got := make([]string, runs)
+	var wg sync.WaitGroup
+	for i := range runs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p, err := nextFree(path, "", 24)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			got[i] = p.String()
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool)
+	for _, p := range got {
+		if seen[p] {
+			t.Errorf("%s was allocated twice", p)
+		}
+		seen[p] = true
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// nthCmd represents the nth command
+var nthCmd = &cobra.Command{
+	Use:   "nth <n>",
+	Short: "return the nth address in a network",
+	Long: `Return the address n places from the start of the network.  A
+negative n counts back from the end, so -1 is the last address; put
+it after "--" so it isn't taken for a flag.  With --skip-network,
+counting starts after the network address, and --start offsets n by
+that many addresses.  Example:
+
+	cidr nth --within 10.0.0.0/24 5
+
+returns
+
+	10.0.0.5
+
+and
+
+	cidr nth --within 10.0.0.0/24 -- -1
+
+returns
+
+	10.0.0.255
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		within, err := cmd.Flags().GetString("within")
+		if err != nil {
+			panic(err)
+		}
+		skip, err := cmd.Flags().GetBool("skip-network")
+		if err != nil {
+			panic(err)
+		}
+		start, err := cmd.Flags().GetUint64("start")
+		if err != nil {
+			panic(err)
+		}
+
+		network, err := parseBlock(within)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		n, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			fmt.Printf("error parsing '%s' -- %s\n", args[0], err)
+			os.Exit(1)
+		}
+
+		addr, err := nth(network, n, start, skip)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s\n", formatIPv4(addr))
+	},
+}
+
+// return the nth address of the block, counting from the end if n < 0.
+// start is added to the resolved index.
+func nth(b block, n int64, start uint64, skipNetwork bool) (uint32, error) {
+	var offset int64
+	if skipNetwork {
+		offset = 1
+	}
+	count := int64(b.size()) - offset
+
+	i := n
+	if i < 0 {
+		i += count
+	}
+	i += int64(start)
+	if i < 0 || i >= count {
+		return 0, fmt.Errorf("%d is outside of %s, which has %d addresses", n, b, count)
+	}
+
+	return b.base + uint32(offset+i), nil
+}
+
+func init() {
+	RootCmd.AddCommand(nthCmd)
+
+	nthCmd.Flags().StringP("within", "w", "", "the network, in CIDR notation")
+	nthCmd.Flags().Bool("skip-network", false, "start counting after the network address")
+	nthCmd.Flags().Uint64("start", 0, "offset added to n")
+}
@@ -0,0 +1,75 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestNth(t *testing.T) {
+	tests := []struct {
+		n     int64
+		start uint64
+		skip  bool
+		want  string
+	}{
+		{5, 0, false, "10.0.0.5"},
+		{5, 0, true, "10.0.0.6"},
+		{0, 0, false, "10.0.0.0"},
+		{0, 0, true, "10.0.0.1"},
+		{255, 0, false, "10.0.0.255"},
+		{-1, 0, false, "10.0.0.255"},
+		{-1, 0, true, "10.0.0.255"},
+		{-256, 0, false, "10.0.0.0"},
+		{5, 10, false, "10.0.0.15"},
+		{-11, 10, false, "10.0.0.255"},
+	}
+
+	network, err := parseBlock("10.0.0.0/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		got, err := nth(network, tt.n, tt.start, tt.skip)
+		if err != nil {
+			t.Errorf("nth(%d, start %d, skip %v): %s", tt.n, tt.start, tt.skip, err)
+			continue
+		}
+		if formatIPv4(got) != tt.want {
+			t.Errorf("nth(%d, start %d, skip %v) = %s, want %s", tt.n, tt.start, tt.skip, formatIPv4(got), tt.want)
+		}
+	}
+}
+
+func TestNthOutOfRange(t *testing.T) {
+	tests := []struct {
+		n     int64
+		start uint64
+		skip  bool
+	}{
+		{256, 0, false},
+		{255, 0, true},
+		{-257, 0, false},
+		{-256, 0, true},
+		{250, 6, false},
+	}
+
+	network, err := parseBlock("10.0.0.0/24")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		if got, err := nth(network, tt.n, tt.start, tt.skip); err == nil {
+			t.Errorf("nth(%d, start %d, skip %v) = %s, expected an error", tt.n, tt.start, tt.skip, formatIPv4(got))
+		}
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/netip"
+	"slices"
+	"strings"
+	"text/template"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// the version of the result schema.  bump it whenever a field of result
+// is renamed, removed or changes meaning; adding a field does not.
+const resultSchemaVersion = 1
+
+// result is the structured (json/yaml) form of the bare command's output.
+// keys are encoded in the order of the struct's fields.
+type result struct {
+	SchemaVersion int          `json:"schema_version" yaml:"schema_version"`
+	Address       string       `json:"address" yaml:"address"`
+	Integer       integer      `json:"integer" yaml:"integer"`
+	Prefix        string       `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	Fields        []fieldValue `json:"fields" yaml:"fields"`
+}
+
+// an integer is an address as a (possibly 128 bit) number, encoded
+// as a plain number in both json and yaml
+type integer struct {
+	*big.Int
+}
+
+func (i integer) MarshalJSON() ([]byte, error) {
+	return []byte(i.String()), nil
+}
+
+func (i integer) MarshalYAML() (interface{}, error) {
+	return &yaml.Node{Kind: yaml.ScalarNode, Value: i.String()}, nil
+}
+
+// format an address as an integer in the given base, e.g. 0xac101041
+func formatInteger(addr netip.Addr, base int) string {
+	n := new(big.Int).SetBytes(addr.AsSlice())
+
+	prefix := ""
+	switch base {
+	case 2:
+		prefix = "0b"
+	case 8:
+		prefix = "0o"
+	case 16:
+		prefix = "0x"
+	}
+	return prefix + n.Text(base)
+}
+
+// reverse the bytes of the address, e.g. to print it as a little
+// endian integer
+func swapBytes(addr netip.Addr) netip.Addr {
+	b := addr.AsSlice()
+	slices.Reverse(b)
+	swapped, _ := netip.AddrFromSlice(b)
+	return swapped
+}
+
+// separate the digits of a decimal count into groups of three with
+// commas, e.g. 16,777,216
+func groupDigits(digits string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+
+	var sb strings.Builder
+	lead := len(digits) % 3
+	if lead > 0 {
+		sb.WriteString(digits[:lead])
+	}
+	for i := lead; i < len(digits); i += 3 {
+		if sb.Len() > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(digits[i : i+3])
+	}
+	return sb.String()
+}
+
+// translate the inputs into a result.  a negative bits omits the prefix.
+func newResult(value, mask, within string, bits int, opts translateOptions) (*result, error) {
+	fields, values, names, err := packFields(value, mask, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := translateAddr(value, mask, within, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	integerAddr := addr
+	if opts.littleEndian {
+		integerAddr = swapBytes(addr)
+	}
+
+	r := &result{
+		SchemaVersion: resultSchemaVersion,
+		Address:       formatAddr(addr, opts),
+		Integer:       integer{new(big.Int).SetBytes(integerAddr.AsSlice())},
+		Fields:        make([]fieldValue, len(fields)),
+	}
+	for i, f := range fields {
+		r.Fields[i] = fieldValue{Name: fieldKey(names, i), Width: f, Value: values[i]}
+	}
+
+	if bits >= 0 {
+		prefix, err := translatePrefix(value, mask, within, bits, opts)
+		if err != nil {
+			return nil, err
+		}
+		r.Prefix = formatPrefix(prefix, opts)
+	}
+
+	return r, nil
+}
+
+// encode v in one of the structured output formats
+func writeEncoded(w io.Writer, v interface{}, output string) error {
+	switch output {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+
+	case "yaml":
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+
+	return fmt.Errorf("unknown output format '%s', expected text, json or yaml", output)
+}
+
+// format v with a text/template, followed by a newline
+func writeTemplate(w io.Writer, v interface{}, text string) error {
+	t, err := template.New("output").Parse(text)
+	if err != nil {
+		return fmt.Errorf("error parsing --output-template -- %s", err)
+	}
+
+	if err := t.Execute(w, v); err != nil {
+		return fmt.Errorf("error executing --output-template -- %s", err)
+	}
+	_, err = fmt.Fprintln(w)
+	return err
+}
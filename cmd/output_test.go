@@ -0,0 +1,196 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"maps"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// compare output with a golden file, or rewrite it with -update
+func checkGolden(t *testing.T, name, output string) {
+	t.Helper()
+
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != string(want) {
+		t.Errorf("output differs from %s; got\n%s\nwant\n%s", path, output, want)
+	}
+}
+
+func TestResultJSONGolden(t *testing.T) {
+	out := runRoot(t, "--mask", "16:region,8:pod,8:host", "--within", "172.16.0.0",
+		"--prefix", "24", "--output", "json", "1.1.1")
+	checkGolden(t, "result.json", out)
+}
+
+func TestResultYAML(t *testing.T) {
+	res, err := newResult("1.1.1", "16:region,8:pod,8:host", "172.16.0.0", 24, translateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var yamlOut, jsonOut bytes.Buffer
+	if err := writeEncoded(&yamlOut, res, "yaml"); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeEncoded(&jsonOut, res, "json"); err != nil {
+		t.Fatal(err)
+	}
+
+	var fromYAML, fromJSON map[string]any
+	if err := yaml.Unmarshal(yamlOut.Bytes(), &fromYAML); err != nil {
+		t.Fatalf("the output is not valid YAML -- %s\n%s", err, yamlOut.String())
+	}
+	if err := json.Unmarshal(jsonOut.Bytes(), &fromJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	// the same keys as the json, with the same values
+	yamlKeys := slices.Sorted(maps.Keys(fromYAML))
+	jsonKeys := slices.Sorted(maps.Keys(fromJSON))
+	if !slices.Equal(yamlKeys, jsonKeys) {
+		t.Errorf("yaml keys %v differ from the json keys %v", yamlKeys, jsonKeys)
+	}
+	if fromYAML["address"] != "172.17.1.1" || fromYAML["prefix"] != "172.17.1.1/24" {
+		t.Errorf("unexpected yaml result %v", fromYAML)
+	}
+	if n, ok := fromYAML["integer"].(int); !ok || n != 2886795521 {
+		t.Errorf("expected the integer as a yaml number, got %#v", fromYAML["integer"])
+	}
+	fields, ok := fromYAML["fields"].([]any)
+	if !ok || len(fields) != 3 {
+		t.Fatalf("expected 3 fields, got %#v", fromYAML["fields"])
+	}
+	if f := fields[0].(map[string]any); f["name"] != "region" || f["width"] != 16 || f["value"] != 1 {
+		t.Errorf("unexpected first field %v", f)
+	}
+}
+
+func TestWriteTemplate(t *testing.T) {
+	res, err := newResult("1.1.1", "16:region,8:pod,8:host", "172.16.0.0", 24, translateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"{{.Address}} ({{.Integer}})", "172.17.1.1 (2886795521)\n"},
+		{"{{.Prefix}}", "172.17.1.1/24\n"},
+		{"{{range .Fields}}{{.Name}}={{.Value}} {{end}}", "region=1 pod=1 host=1 \n"},
+	}
+	for _, tt := range tests {
+		var sb strings.Builder
+		if err := writeTemplate(&sb, res, tt.text); err != nil {
+			t.Errorf("writeTemplate(%q): %s", tt.text, err)
+			continue
+		}
+		if sb.String() != tt.want {
+			t.Errorf("writeTemplate(%q) = %q, want %q", tt.text, sb.String(), tt.want)
+		}
+	}
+
+	for text, want := range map[string]string{
+		"{{.Address":     "error parsing --output-template",
+		"{{.NoSuchKey}}": "error executing --output-template",
+	} {
+		err := writeTemplate(io.Discard, res, text)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("writeTemplate(%q) = %v, want an error containing %q", text, err, want)
+		}
+	}
+}
+
+func TestFormatInteger(t *testing.T) {
+	addr := netip.MustParseAddr("172.16.16.65")
+	tests := []struct {
+		base int
+		want string
+	}{
+		{2, "0b10101100000100000001000001000001"},
+		{10, "2886733889"},
+		{16, "0xac101041"},
+		{8, "0o25404010101"},
+		{36, "1bqoq4h"},
+	}
+
+	for _, tt := range tests {
+		if got := formatInteger(addr, tt.base); got != tt.want {
+			t.Errorf("formatInteger(%s, %d) = %s, want %s", addr, tt.base, got, tt.want)
+		}
+	}
+
+	if got := runRoot(t, "--mask", "8.8.8.8", "--integer", "--output-base", "16", "172.16.16.65"); got != "0xac101041\n" {
+		t.Errorf("--output-base 16 printed %q, want 0xac101041", got)
+	}
+}
+
+func TestGroupDigits(t *testing.T) {
+	tests := []struct {
+		digits, want string
+	}{
+		{"0", "0"},
+		{"999", "999"},
+		{"1000", "1,000"},
+		{"65536", "65,536"},
+		{"256000", "256,000"},
+		{"16777216", "16,777,216"},
+		{"4294967296", "4,294,967,296"},
+		{"340282366920938463463374607431768211456", "340,282,366,920,938,463,463,374,607,431,768,211,456"},
+	}
+
+	for _, tt := range tests {
+		if got := groupDigits(tt.digits); got != tt.want {
+			t.Errorf("groupDigits(%s) = %s, want %s", tt.digits, got, tt.want)
+		}
+	}
+}
+
+func TestGroupDigitsFlag(t *testing.T) {
+	args := []string{"cost", "--within", "10.0.0.0/8", "--allocated", "10.0.0.0/16"}
+	if got, want := runRoot(t, args...), "65536 of 16777216 addresses allocated (0.39%)\n"; got != want {
+		t.Errorf("raw counts printed %q, want %q", got, want)
+	}
+	if got, want := runRoot(t, append(args, "--group-digits")...), "65,536 of 16,777,216 addresses allocated (0.39%)\n"; got != want {
+		t.Errorf("--group-digits printed %q, want %q", got, want)
+	}
+	if got, want := runRoot(t, "cost", "--within", "10.0.0.0/8", "--allocated", "10.0.0.0/16", "--group-digits", "--output", "json"), `"allocated": 65536`; !strings.Contains(got, want) {
+		t.Errorf("--group-digits changed json output: %q", got)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// overlapCmd represents the overlap command
+var overlapCmd = &cobra.Command{
+	Use:   "overlap <cidr> <cidr>...",
+	Short: "report any intersecting networks",
+	Long: `Compare two or more CIDRs and print each pair which overlaps.  The
+exit status is non-zero if any overlap is found, so the command may be
+used as a validation gate.  Example:
+
+	cidr overlap 10.0.0.0/24 10.0.0.128/25
+
+returns
+
+	10.0.0.0/24 overlaps 10.0.0.128/25
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) < 2 {
+			cmd.Usage()
+			return
+		}
+
+		blocks := make([]block, len(args))
+		for i, arg := range args {
+			var err error
+			blocks[i], err = parseBlock(arg)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+		}
+
+		pairs := overlaps(blocks)
+		for _, p := range pairs {
+			if resultOnly {
+				fmt.Printf("%s %s\n", p[0], p[1])
+				continue
+			}
+			fmt.Printf("%s overlaps %s\n", p[0], p[1])
+		}
+		if len(pairs) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// return every pair of blocks which share an address
+func overlaps(blocks []block) [][2]block {
+	var pairs [][2]block
+	for i, a := range blocks {
+		for _, b := range blocks[i+1:] {
+			if a.span().overlaps(b.span()) {
+				pairs = append(pairs, [2]block{a, b})
+			}
+		}
+	}
+	return pairs
+}
+
+func init() {
+	RootCmd.AddCommand(overlapCmd)
+}
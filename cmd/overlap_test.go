@@ -0,0 +1,51 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestOverlaps(t *testing.T) {
+	tests := []struct {
+		name  string
+		cidrs []string
+		want  int
+	}{
+		{"nested", []string{"10.0.0.0/24", "10.0.0.128/25"}, 1},
+		{"identical", []string{"10.0.0.0/24", "10.0.0.0/24"}, 1},
+		{"adjacent", []string{"10.0.0.0/25", "10.0.0.128/25"}, 0},
+		{"disjoint", []string{"10.0.0.0/24", "192.168.0.0/16"}, 0},
+		{"nested twice", []string{"10.0.0.0/8", "10.1.0.0/16", "10.1.1.0/24"}, 3},
+	}
+
+	for _, tt := range tests {
+		blocks := make([]block, len(tt.cidrs))
+		for i, c := range tt.cidrs {
+			b, err := parseBlock(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			blocks[i] = b
+		}
+
+		pairs := overlaps(blocks)
+		if len(pairs) != tt.want {
+			t.Errorf("%s: %v has %d overlapping pairs, want %d", tt.name, tt.cidrs, len(pairs), tt.want)
+		}
+		for _, p := range pairs {
+			if !p[0].span().overlaps(p[1].span()) {
+				t.Errorf("%s: reported %s and %s, which don't overlap", tt.name, p[0], p[1])
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// packCmd represents the pack command
+var packCmd = &cobra.Command{
+	Use:   "pack <value>",
+	Short: "pack a value into the mask's fields and return the address",
+	Long: `Pack each field of the value into the bits the mask gives it and OR
+in the within, returning the address.  unpack reverses it.  The bare
+command is an alias of pack, and the two take the same flags.  Example:
+
+	cidr pack --mask 12.8.6.6 --within 172.16.0.0 0.1.1.1
+
+returns
+
+	172.16.16.65
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+		RootCmd.Run(cmd, args)
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(packCmd)
+}
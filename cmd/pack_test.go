@@ -0,0 +1,72 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPackAndUnpack(t *testing.T) {
+	maskFile := filepath.Join(t.TempDir(), "mask")
+	if err := os.WriteFile(maskFile, []byte("12.8.6.6 # region.pod.rack.host\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		flags   string
+		value   string
+		address string
+		fields  string // unpack's --result-only output
+	}{
+		{"--mask 12.8.6.6 --within 172.16.0.0", "0.1.1.1", "172.16.16.65", "0 1 1 1"},
+		{"--mask 12:region,8:pod,6:rack,6:host --within 172.16.0.0", "0.1.2.3", "172.16.16.131", "0 1 2 3"},
+		{"--mask 8.8.8.8", "10.20.30.40", "10.20.30.40", "10 20 30 40"},
+		{"--mask-file " + maskFile + " --within 172.16.0.0", "0.255.63.63", "172.31.255.255", "0 255 63 63"},
+		{"--derive-mask 255.255.240.0 --within 10.0.0.0", "0.0.1.257", "10.0.17.1", "0 0 1 257"},
+		{"--mask 16.8.8 --field-order lsb --within 10.0.0.0", "1.2.0", "10.2.0.1", "1 2 0"},
+		{"--mask 1.1.1.1 --width-unit bytes", "1.2.3.4", "1.2.3.4", "1 2 3 4"},
+		{"--mask 16.16.16.16.16.16.16.16 --within 2001:db8::", "0.0.0.0.0.0.1.2", "2001:db8::1:2", "0 0 0 0 0 0 1 2"},
+	}
+
+	for _, tt := range tests {
+		flags := strings.Fields(tt.flags)
+		packed := runRoot(t, append(append([]string{"pack"}, flags...), tt.value)...)
+		if packed != tt.address+"\n" {
+			t.Errorf("pack %s %s printed %q, want %s", tt.flags, tt.value, packed, tt.address)
+		}
+		if bare := runRoot(t, append(flags, tt.value)...); bare != packed {
+			t.Errorf("cidr %s %s printed %q, but pack printed %q", tt.flags, tt.value, bare, packed)
+		}
+
+		// the same command line with the verb changed reverses it
+		unpacked := runRoot(t, append(append([]string{"--result-only", "unpack"}, flags...), tt.address)...)
+		if unpacked != tt.fields+"\n" {
+			t.Errorf("unpack %s %s printed %q, want %s", tt.flags, tt.address, unpacked, tt.fields)
+		}
+	}
+}
+
+func TestUnpackSharesPackFlags(t *testing.T) {
+	for _, name := range []string{"mask", "mask-file", "derive-mask", "mask-type", "within", "field-order", "family", "width-unit", "lenient"} {
+		if packCmd.Flags().Lookup(name) == nil {
+			t.Errorf("pack has no --%s", name)
+		}
+		if decomposeCmd.Flags().Lookup(name) == nil {
+			t.Errorf("unpack has no --%s, which pack has", name)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// prefixOfCmd represents the prefix-of command
+var prefixOfCmd = &cobra.Command{
+	Use:   "prefix-of <within>",
+	Short: "report the prefix length fixed by a within",
+	Long: `Report how many bits of the address a within fixes.  A within in
+CIDR notation fixes the bits of its prefix.  Otherwise it is split into
+fields by --within-mask, octets by default.  Every field up to and
+including the last field with a non-zero value is "fixed"; the fields
+after it are "variable" and left for the value to fill in.  The prefix
+length is the sum of the fixed field widths.  mask-bits and hostmask
+use the same rule.  Example:
+
+	cidr prefix-of --within-mask 12.20 2753.0
+
+returns
+
+	12
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		withinMask, err := cmd.Flags().GetString("within-mask")
+		if err != nil {
+			panic(err)
+		}
+
+		bits, err := prefixOf(args[0], withinMask)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%d\n", bits)
+	},
+}
+
+// the prefix length fixed by the within, split into fields by withinMask
+func prefixOf(within, withinMask string) (int, error) {
+	fields, err := parseMask(withinMask)
+	if err != nil {
+		return 0, err
+	}
+	return withinPrefix(within, fields)
+}
+
+func init() {
+	RootCmd.AddCommand(prefixOfCmd)
+
+	prefixOfCmd.Flags().String("within-mask", "8.8.8.8", "bitmask splitting the within into fields")
+}
@@ -0,0 +1,59 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestPrefixOf(t *testing.T) {
+	tests := []struct {
+		within, withinMask string
+		want               int
+	}{
+		{"172.16.0.0", "8.8.8.8", 16},
+		{"10.0.0.0", "8.8.8.8", 8},
+		{"10.0.0.1", "8.8.8.8", 32},
+		{"0.0.0.0", "8.8.8.8", 0},
+		{"2753.0", "12.20", 12},
+		{"2753.1", "12.20", 32},
+		{"172.16.0.0/12", "8.8.8.8", 12},
+	}
+
+	for _, tt := range tests {
+		got, err := prefixOf(tt.within, tt.withinMask)
+		if err != nil {
+			t.Errorf("prefixOf(%q, %q): %s", tt.within, tt.withinMask, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("prefixOf(%q, %q) = %d, want %d", tt.within, tt.withinMask, got, tt.want)
+		}
+	}
+}
+
+func TestPrefixOfErrors(t *testing.T) {
+	tests := []struct {
+		within, withinMask string
+	}{
+		{"172.16.0", "8.8.8.8"}, // too few fields
+		{"4096.0", "12.20"},     // 4096 doesn't fit in 12 bits
+		{"172.16.0.0/33", "8.8.8.8"},
+		{"172.16.0.0", "8.8.x.8"},
+	}
+
+	for _, tt := range tests {
+		if got, err := prefixOf(tt.within, tt.withinMask); err == nil {
+			t.Errorf("prefixOf(%q, %q) = %d, expected an error", tt.within, tt.withinMask, got)
+		}
+	}
+}
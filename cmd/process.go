@@ -0,0 +1,240 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// processCmd represents the process command
+var processCmd = &cobra.Command{
+	Use:   "process",
+	Short: "translate rows which each carry their own mask and within",
+	Long: `Read rows of value<TAB>mask<TAB>within and print the result of each
+row, one per line.  The within may be omitted, in which case it is
+0.0.0.0.  Rows which can't be translated are reported on stderr with
+their line number and the exit status is non-zero.  The bare command's
+translation flags, such as --field-order, --field-base and --width-unit,
+apply to every row, and an auto --family follows each row's own within;
+the row's mask and within take the place of --mask and --within.
+Example:
+
+	printf '0.1.1.1\t12.8.6.6\t172.16.0.0\n' | cidr process --tsv -
+
+returns
+
+	172.16.16.65
+
+With --stdin-format json, stdin is read as JSON lines instead, each an
+object with value, mask and (optional) within keys, and one JSON object
+is printed per line: the value with its result, or the line number with
+its error.  Example:
+
+	echo '{"value":"0.1.1.1","mask":"12.8.6.6","within":"172.16.0.0"}' | cidr process --stdin-format json
+
+returns
+
+	{"value":"0.1.1.1","result":"172.16.16.65"}
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		file, err := cmd.Flags().GetString("tsv")
+		if err != nil {
+			panic(err)
+		}
+		format, err := cmd.Flags().GetString("stdin-format")
+		if err != nil {
+			panic(err)
+		}
+
+		opts, family, err := processOptionsFromFlags(cmd)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		if format == "json" {
+			failed, err := processJSON(os.Stdin, os.Stdout, opts, family)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+			if failed > 0 {
+				os.Exit(1)
+			}
+			return
+		}
+		if format != "tsv" {
+			fmt.Printf("unknown stdin format '%s', expected tsv or json\n", format)
+			os.Exit(1)
+		}
+
+		var in io.Reader = os.Stdin
+		if len(file) > 0 && file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+			defer f.Close()
+			in = f
+		}
+
+		failed, err := processTSV(in, os.Stdout, os.Stderr, opts, family)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		if failed > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+// the translate options of the bare command's flags, shared by every row,
+// and the --family name, which each row resolves against its own within
+func processOptionsFromFlags(cmd *cobra.Command) (translateOptions, string, error) {
+	opts, err := translateOptionsFromFlags(cmd, "0.0.0.0")
+	if err != nil {
+		return translateOptions{}, "", err
+	}
+	family, err := cmd.Flags().GetString("family")
+	if err != nil {
+		panic(err)
+	}
+	return opts, family, nil
+}
+
+// the options for one row: opts, with the family resolved from the row's
+// within
+func rowOptions(opts translateOptions, family, within string) (translateOptions, error) {
+	f, err := parseFamily(family, within)
+	if err != nil {
+		return translateOptions{}, err
+	}
+	opts.family = f
+	return opts, nil
+}
+
+// translate each row with opts, writing results to out and row errors
+// to errs.  returns the number of rows which failed.
+func processTSV(in io.Reader, out, errs io.Writer, opts translateOptions, family string) (int, error) {
+	r := csv.NewReader(in)
+	r.Comma = '\t'
+	r.Comment = '#'
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	r.ReuseRecord = true
+
+	failed := 0
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			return failed, nil
+		}
+		if err != nil {
+			return failed, err
+		}
+		line, _ := r.FieldPos(0)
+
+		if len(row) < 2 || len(row) > 3 {
+			fmt.Fprintf(errs, "line %d: expected value, mask and within, found %d fields\n", line, len(row))
+			failed++
+			continue
+		}
+		within := "0.0.0.0"
+		if len(row) == 3 {
+			within = row[2]
+		}
+
+		rowOpts, err := rowOptions(opts, family, within)
+		if err != nil {
+			return failed, err
+		}
+		str, err := translate(row[0], row[1], within, rowOpts)
+		if err != nil {
+			fmt.Fprintf(errs, "line %d: %s\n", line, err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(out, "%s\n", str)
+	}
+}
+
+// one job of --stdin-format json input
+type job struct {
+	Value  string `json:"value"`
+	Mask   string `json:"mask"`
+	Within string `json:"within"`
+}
+
+// the output for one job; exactly one of Result and Error is set
+type jobResult struct {
+	Line   int    `json:"line,omitempty"`
+	Value  string `json:"value,omitempty"`
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// translate each JSON line of in with opts, writing a JSON line to out
+// for each.  returns the number of lines which failed.
+func processJSON(in io.Reader, out io.Writer, opts translateOptions, family string) (int, error) {
+	enc := json.NewEncoder(out)
+	failed := 0
+	err := scanLines(in, false, func(line int, text string) error {
+		var j job
+		dec := json.NewDecoder(strings.NewReader(text))
+		dec.DisallowUnknownFields()
+		err := dec.Decode(&j)
+		if err == nil && dec.More() {
+			err = fmt.Errorf("more than one object on the line")
+		}
+		if err == nil && (len(j.Value) == 0 || len(j.Mask) == 0) {
+			err = fmt.Errorf("expected value and mask keys")
+		}
+		if err != nil {
+			failed++
+			return enc.Encode(jobResult{Line: line, Error: err.Error()})
+		}
+
+		if len(j.Within) == 0 {
+			j.Within = "0.0.0.0"
+		}
+		jobOpts, err := rowOptions(opts, family, j.Within)
+		if err != nil {
+			return err
+		}
+		str, err := translate(j.Value, j.Mask, j.Within, jobOpts)
+		if err != nil {
+			failed++
+			return enc.Encode(jobResult{Line: line, Value: j.Value, Error: err.Error()})
+		}
+		return enc.Encode(jobResult{Value: j.Value, Result: str})
+	})
+	return failed, err
+}
+
+func init() {
+	RootCmd.AddCommand(processCmd)
+
+	processCmd.Flags().String("tsv", "-", "file of value<TAB>mask<TAB>within rows ('-' for stdin)")
+	processCmd.Flags().String("stdin-format", "tsv", "read stdin as tsv rows, or as json lines of value, mask and within")
+}
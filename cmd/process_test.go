@@ -0,0 +1,131 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProcessTSV(t *testing.T) {
+	in := strings.Join([]string{
+		"0.1.1.1\t12.8.6.6\t172.16.0.0",
+		"1.2.3.4\t8.8.8.8",
+		"# a comment",
+		"1.2.3\t8.8.8.8\t0.0.0.0",
+		"1.2.3.4",
+		"1.2.3.4\t8.8.8.8\t0.0.0.0\textra",
+		"0.1\t16.16\t10.0.0.0",
+		"",
+	}, "\n")
+
+	var out, errs strings.Builder
+	failed, err := processTSV(strings.NewReader(in), &out, &errs, translateOptions{}, "auto")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "172.16.16.65\n1.2.3.4\n10.0.0.1\n"; out.String() != want {
+		t.Errorf("results %q, want %q", out.String(), want)
+	}
+	if failed != 3 {
+		t.Errorf("%d rows failed, want 3", failed)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(errs.String(), "\n"), "\n")
+	want := []string{
+		"line 4: mask defines 4 fields but value only provides 3",
+		"line 5: expected value, mask and within, found 1 fields",
+		"line 6: expected value, mask and within, found 4 fields",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("errors %q, want %q", lines, want)
+	}
+	for i := range want {
+		if !strings.HasPrefix(lines[i], want[i]) {
+			t.Errorf("error %q, want %q", lines[i], want[i])
+		}
+	}
+}
+
+func TestProcessJSON(t *testing.T) {
+	in := strings.Join([]string{
+		`{"value": "0.1.1.1", "mask": "12.8.6.6", "within": "172.16.0.0"}`,
+		`{"value": "1.2.3.4", "mask": "8.8.8.8"}`,
+		`{"value": "1.2.3", "mask": "8.8.8.8"}`,
+		`{"value": "1.2.3.4", "mask": "8.8.8.8"`,
+		`not json`,
+		`{"value": "1.2.3.4"}`,
+		`{"value": "1.2.3.4", "mask": "8.8.8.8", "prefix": 24}`,
+		`{"value": "1.2.3.4", "mask": "8.8.8.8"} {"value": "1.2.3.4", "mask": "8.8.8.8"}`,
+		`{"value": "0.1", "mask": "16.16", "within": "10.0.0.0"}`,
+	}, "\n")
+
+	var out strings.Builder
+	failed, err := processJSON(strings.NewReader(in), &out, translateOptions{}, "auto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed != 6 {
+		t.Errorf("%d lines failed, want 6", failed)
+	}
+
+	want := []string{
+		`{"value":"0.1.1.1","result":"172.16.16.65"}`,
+		`{"value":"1.2.3.4","result":"1.2.3.4"}`,
+		`{"line":3,"value":"1.2.3","error":"mask defines 4 fields but value only provides 3`,
+		`{"line":4,"error":"unexpected EOF"}`,
+		`{"line":5,"error":"invalid character 'o' in literal null (expecting 'u')"}`,
+		`{"line":6,"error":"expected value and mask keys"}`,
+		`{"line":7,"error":"json: unknown field \"prefix\""}`,
+		`{"line":8,"error":"more than one object on the line"}`,
+		`{"value":"0.1","result":"10.0.0.1"}`,
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("processJSON wrote\n%s\nwant %d lines", out.String(), len(want))
+	}
+	for i := range want {
+		if !strings.HasPrefix(lines[i], want[i]) {
+			t.Errorf("line %d is %s, want %s", i+1, lines[i], want[i])
+		}
+	}
+}
+
+func TestProcessTSVOptions(t *testing.T) {
+	tests := []struct {
+		row    string
+		opts   translateOptions
+		family string
+		want   string
+	}{
+		{"1.2\t16.16", translateOptions{lsbFirst: true}, "auto", "0.2.0.1"},
+		{"ff.1\t16.16", translateOptions{fieldBase: 16}, "auto", "0.255.0.1"},
+		{"1.2.3.4\t1.1.1.1", translateOptions{widthUnit: 8}, "auto", "1.2.3.4"},
+		{"1.2\t64.64\t2001:db8::", translateOptions{}, "auto", "2001:db8:0:1::2"},
+		{"1.2\t64.64", translateOptions{}, "6", "::1:0:0:0:2"},
+	}
+
+	for _, tt := range tests {
+		var out, errs strings.Builder
+		failed, err := processTSV(strings.NewReader(tt.row+"\n"), &out, &errs, tt.opts, tt.family)
+		if err != nil || failed != 0 {
+			t.Errorf("processTSV(%q) failed %d rows, %v: %s", tt.row, failed, err, errs.String())
+			continue
+		}
+		if got := strings.TrimSuffix(out.String(), "\n"); got != tt.want {
+			t.Errorf("processTSV(%q) = %s, want %s", tt.row, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// the flags of the bare command which a profile may save
+var profileFlags = []string{"mask", "mask-type", "within", "field-order", "family", "width-unit"}
+
+// profileCmd represents the profile command
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "manage named sets of flags saved in the config file",
+}
+
+// profileSaveCmd represents the profile save command
+var profileSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "save flags as a named profile",
+	Long: `Save the flags given as a named profile in the config file (by
+default $HOME/.cidr.yaml, which is created if need be), replacing any
+profile of the same name.  The bare command then uses them with
+--profile; flags given on its command line take precedence.  Example:
+
+	cidr profile save prod --mask 12.8.6.6 --within 172.16.0.0
+	cidr --profile prod 0.1.1.1
+
+returns
+
+	172.16.16.65
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+		if noConfig {
+			fmt.Printf("profiles are saved in the config file, which --no-config ignores\n")
+			os.Exit(1)
+		}
+
+		settings := make(map[string]string)
+		for _, name := range profileFlags {
+			if cmd.Flags().Changed(name) {
+				settings[name] = cmd.Flags().Lookup(name).Value.String()
+			}
+		}
+		if len(settings) == 0 {
+			fmt.Printf("no flags to save; give at least one of --%s\n", joinFlags(profileFlags))
+			os.Exit(1)
+		}
+
+		if err := saveProfile(args[0], settings); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// the flag names as a list for messages
+func joinFlags(names []string) string {
+	str := ""
+	for i, name := range names {
+		switch {
+		case i == 0:
+		case i == len(names)-1:
+			str += " or --"
+		default:
+			str += ", --"
+		}
+		str += name
+	}
+	return str
+}
+
+// write the profile to the config file in use, or to the default one
+func saveProfile(name string, settings map[string]string) error {
+	viper.Set("profiles."+name, settings)
+
+	path := viper.ConfigFileUsed()
+	if len(path) == 0 {
+		home, err := homeDir()
+		if err != nil {
+			return fmt.Errorf("can't find the home directory for the config file -- %s", err)
+		}
+		path = filepath.Join(home, ".cidr.yaml")
+	}
+
+	if err := viper.WriteConfigAs(path); err != nil {
+		return fmt.Errorf("error writing config file %s: %s", path, err)
+	}
+	logger.Info("saved profile", "profile", name, "file", path)
+	return nil
+}
+
+// set the command's flags from the profile, except those given on the
+// command line.  a profile's mask is skipped when any flag selecting
+// the mask was given.
+func applyProfile(cmd *cobra.Command, name string) error {
+	settings := viper.GetStringMapString("profiles." + name)
+	if len(settings) == 0 {
+		return fmt.Errorf("no profile named '%s' in the config file", name)
+	}
+
+	for key, value := range settings {
+		if !slices.Contains(profileFlags, key) {
+			return fmt.Errorf("profile '%s' sets '%s', which a profile can't set", name, key)
+		}
+		if cmd.Flags().Changed(key) || key == "mask" && countChanged(cmd, maskFlags...) > 0 {
+			continue
+		}
+		if err := cmd.Flags().Set(key, value); err != nil {
+			return fmt.Errorf("profile '%s' has an invalid %s -- %s", name, key, err)
+		}
+		logger.Debug("profile", "profile", name, "flag", key, "value", value)
+	}
+	return nil
+}
+
+func init() {
+	RootCmd.AddCommand(profileCmd)
+	profileCmd.AddCommand(profileSaveCmd)
+
+	profileSaveCmd.Flags().StringP("mask", "m", "", "bitmask for translation")
+	profileSaveCmd.Flags().String("mask-type", "", "how to read --mask: widths or netmask")
+	profileSaveCmd.Flags().StringP("within", "w", "", "result is OR'ed with this CIDR")
+	profileSaveCmd.Flags().String("field-order", "", "pack the first field into the most (msb) or least (lsb) significant bits")
+	profileSaveCmd.Flags().String("family", "", "address family: auto, 4 or 6")
+	profileSaveCmd.Flags().String("width-unit", "", "the unit of the mask's field widths: bits or bytes")
+}
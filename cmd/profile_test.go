@@ -0,0 +1,101 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// point the config file at a fresh home directory for the test
+func tempHome(t *testing.T) string {
+	home := t.TempDir()
+	saved := homeDir
+	homeDir = func() (string, error) { return home, nil }
+	viper.Reset()
+	t.Cleanup(func() {
+		homeDir = saved
+		viper.Reset()
+	})
+	return home
+}
+
+func TestProfileSaveAndLoad(t *testing.T) {
+	home := tempHome(t)
+	path := filepath.Join(home, ".cidr.yaml")
+
+	runRoot(t, "profile", "save", "prod", "--mask", "12.8.6.6", "--within", "172.16.0.0")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("profile save didn't write the config file: %s", err)
+	}
+	for _, want := range []string{"prod:", "mask: 12.8.6.6", "within: 172.16.0.0"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("the config file is missing %q:\n%s", want, data)
+		}
+	}
+
+	// a second profile goes into the same file, keeping the first
+	viper.Reset()
+	runRoot(t, "profile", "save", "lab", "--mask", "16.8.8", "--within", "10.0.0.0")
+
+	viper.Reset()
+	want := "Using config file: " + path + "\n172.16.16.65\n"
+	if got := runRoot(t, "--profile", "prod", "0.1.1.1"); got != want {
+		t.Errorf("--profile prod printed %q, want %q", got, want)
+	}
+	viper.Reset()
+	want = "Using config file: " + path + "\n10.0.1.1\n"
+	if got := runRoot(t, "--profile", "lab", "0.1.1"); got != want {
+		t.Errorf("--profile lab printed %q, want %q", got, want)
+	}
+
+	// flags on the command line override the profile's
+	viper.Reset()
+	want = "Using config file: " + path + "\n10.0.16.65\n"
+	if got := runRoot(t, "--profile", "prod", "--within", "10.0.0.0", "0.1.1.1"); got != want {
+		t.Errorf("--profile prod --within 10.0.0.0 printed %q, want %q", got, want)
+	}
+}
+
+func TestProfileConfigFlag(t *testing.T) {
+	tempHome(t)
+	path := filepath.Join(t.TempDir(), "cidr.yaml")
+	if err := os.WriteFile(path, []byte("profiles: {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	runRoot(t, "--config", path, "profile", "save", "prod", "--mask", "12.8.6.6")
+	viper.Reset()
+	want := "Using config file: " + path + "\n172.16.16.65\n"
+	if got := runRoot(t, "--config", path, "--profile", "prod", "--within", "172.16.0.0", "0.1.1.1"); got != want {
+		t.Errorf("--profile prod printed %q, want %q", got, want)
+	}
+}
+
+func TestApplyProfileErrors(t *testing.T) {
+	tempHome(t)
+	viper.Set("profiles.bad", map[string]string{"prefix": "24"})
+
+	if err := applyProfile(RootCmd, "missing"); err == nil || !strings.Contains(err.Error(), "no profile named 'missing'") {
+		t.Errorf("applyProfile of a missing profile: %v", err)
+	}
+	if err := applyProfile(RootCmd, "bad"); err == nil || !strings.Contains(err.Error(), "can't set") {
+		t.Errorf("applyProfile of a profile setting --prefix: %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// ptrCmd represents the ptr command
+var ptrCmd = &cobra.Command{
+	Use:   "ptr <address>",
+	Short: "print the reverse DNS (PTR) name of an address",
+	Long: `Print the name under which an address's PTR record lives: the
+reversed octets under in-addr.arpa for IPv4, or the reversed nibbles
+under ip6.arpa for IPv6.  Example:
+
+	cidr ptr 172.16.16.65
+
+returns
+
+	65.16.16.172.in-addr.arpa
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		name, err := ptrName(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s\n", name)
+	},
+}
+
+// the reverse DNS name of the address
+func ptrName(address string) (string, error) {
+	addr, err := netip.ParseAddr(address)
+	if err != nil {
+		return "", fmt.Errorf("'%s' is not a valid address", address)
+	}
+	addr = addr.WithZone("")
+
+	if addr.Is4() {
+		a := addr.As4()
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa", a[3], a[2], a[1], a[0]), nil
+	}
+
+	const hex = "0123456789abcdef"
+	a := addr.As16()
+	labels := make([]string, 0, 33)
+	for i := len(a) - 1; i >= 0; i-- {
+		labels = append(labels, string(hex[a[i]&0x0f]), string(hex[a[i]>>4]))
+	}
+	labels = append(labels, "ip6.arpa")
+
+	return strings.Join(labels, "."), nil
+}
+
+func init() {
+	RootCmd.AddCommand(ptrCmd)
+}
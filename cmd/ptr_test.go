@@ -0,0 +1,45 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestPtrName(t *testing.T) {
+	tests := []struct {
+		address, want string
+	}{
+		{"172.16.16.65", "65.16.16.172.in-addr.arpa"},
+		{"10.0.0.1", "1.0.0.10.in-addr.arpa"},
+		{"2001:db8::567:89ab", "b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa"},
+		{"::1", "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.ip6.arpa"},
+		{"fe80::1%eth0", "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.e.f.ip6.arpa"},
+	}
+
+	for _, tt := range tests {
+		got, err := ptrName(tt.address)
+		if err != nil {
+			t.Errorf("ptrName(%q): %s", tt.address, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ptrName(%q) = %s, want %s", tt.address, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"10.0.0", "10.0.0.256", "2001:db8:::1", ""} {
+		if got, err := ptrName(bad); err == nil {
+			t.Errorf("ptrName(%q) = %s, expected an error", bad, got)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// replCmd represents the repl command
+var replCmd = &cobra.Command{
+	Use:   "repl",
+	Short: "translate values interactively, keeping the mask and within",
+	Long: `Read values from stdin, one per line, and print the translation of
+each with the session's mask and within.  The session starts with the
+--mask and --within given and is controlled with:
+
+	:mask <mask>      use a new mask
+	:within <within>  use a new within
+	:show             print the mask and within
+	:quit             leave (as does end of input)
+
+An error is printed and the session continues.  Example:
+
+	printf ':mask 12.8.6.6\n:within 172.16.0.0\n0.1.1.1\n' | cidr repl
+
+returns
+
+	172.16.16.65
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		mask, err := cmd.Flags().GetString("mask")
+		if err != nil {
+			panic(err)
+		}
+		within, err := cmd.Flags().GetString("within")
+		if err != nil {
+			panic(err)
+		}
+
+		if err := repl(os.Stdin, os.Stdout, mask, within); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// run the read loop until :quit or the end of in
+func repl(in io.Reader, out io.Writer, mask, within string) error {
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 {
+			continue
+		}
+
+		if !strings.HasPrefix(line, ":") {
+			str, err := translate(line, mask, within, translateOptions{})
+			if err != nil {
+				fmt.Fprintf(out, "error: %s\n", err)
+				continue
+			}
+			fmt.Fprintf(out, "%s\n", str)
+			continue
+		}
+
+		command, arg, _ := strings.Cut(line, " ")
+		arg = strings.TrimSpace(arg)
+		switch command {
+		case ":quit", ":q":
+			return nil
+		case ":show":
+			fmt.Fprintf(out, "mask %s, within %s\n", mask, within)
+		case ":mask":
+			if _, _, err := parseNamedMask(arg); err != nil {
+				fmt.Fprintf(out, "error: %s\n", err)
+				continue
+			}
+			mask = stripMaskComment(arg)
+		case ":within":
+			w, _, err := splitWithin(arg, 32)
+			if err == nil {
+				_, err = parseWithin(w, nil, nil)
+			}
+			if err != nil {
+				fmt.Fprintf(out, "error: %s\n", err)
+				continue
+			}
+			within = arg
+		default:
+			fmt.Fprintf(out, "error: unknown command '%s', expected :mask, :within, :show or :quit\n", command)
+		}
+	}
+
+	return scanner.Err()
+}
+
+func init() {
+	RootCmd.AddCommand(replCmd)
+
+	replCmd.Flags().StringP("mask", "m", "8:13:4:7", "the session's initial bitmask")
+	replCmd.Flags().StringP("within", "w", "0.0.0.0", "the session's initial within")
+}
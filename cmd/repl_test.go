@@ -0,0 +1,71 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRepl(t *testing.T) {
+	script := strings.Join([]string{
+		"1.2.3.4",
+		":mask 12.8.6.6",
+		":within 172.16.0.0",
+		"",
+		"0.1.1.1",
+		":show",
+		":mask 8.8.x",
+		"0.1.1",
+		":within 172.16.0.0/12",
+		"1.1.1.1",
+		":bogus",
+		":quit",
+		"0.2.2.2",
+	}, "\n")
+
+	var out strings.Builder
+	if err := repl(strings.NewReader(script), &out, "8.8.8.8", "0.0.0.0"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"1.2.3.4",
+		"172.16.16.65",
+		"mask 12.8.6.6, within 172.16.0.0",
+		"error: ",
+		"error: mask defines 4 fields but value only provides 3",
+		"error: the value sets bits in the first 12 bits",
+		"error: unknown command ':bogus'",
+	}
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("the session printed\n%s\nwant %d lines", out.String(), len(want))
+	}
+	for i := range want {
+		if !strings.HasPrefix(lines[i], want[i]) {
+			t.Errorf("line %d is %q, want %q", i+1, lines[i], want[i])
+		}
+	}
+}
+
+func TestReplEOF(t *testing.T) {
+	var out strings.Builder
+	if err := repl(strings.NewReader("0.1.1.1"), &out, "12.8.6.6", "172.16.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "172.16.16.65\n" {
+		t.Errorf("got %q", out.String())
+	}
+}
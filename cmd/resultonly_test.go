@@ -0,0 +1,78 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestResultOnly(t *testing.T) {
+	tests := []struct {
+		args      string
+		decorated string // the output without --result-only
+		bare      string
+	}{
+		{"--mask 12.8.6.6 --within 172.16.0.0 --explain 0.1.1.1",
+			"field #0 (12 bits) = 0\nfield #1 (8 bits) = 1\nfield #2 (6 bits) = 1\nfield #3 (6 bits) = 1\n172.16.16.65\n",
+			"172.16.16.65\n"},
+		{"classful 192.168.1.1", "class C, natural mask /24, network 192.168.1.0/24\n", "192.168.1.0/24\n"},
+		{"classful 240.0.0.1", "class E (reserved), no natural mask\n", "E (reserved)\n"},
+		{"cost --within 10.0.0.0/16 --allocated 10.0.0.0/24 10.0.1.0/24",
+			"512 of 65536 addresses allocated (0.78%)\n", "0.78\n"},
+		{"unpack --mask 12:region,8:pod,6:rack,6:host --within 172.16.0.0 172.16.16.65",
+			"region=0 pod=1 rack=1 host=1\n", "0 1 1 1\n"},
+		{"equal-split-table --within 10.0.0.0/23 --subnet /24",
+			"INDEX  SUBNET       FIRST     LAST\n0      10.0.0.0/24  10.0.0.0  10.0.0.255\n1      10.0.1.0/24  10.0.1.0  10.0.1.255\n",
+			"0  10.0.0.0/24  10.0.0.0  10.0.0.255\n1  10.0.1.0/24  10.0.1.0  10.0.1.255\n"},
+		{"gaps --within 10.0.0.0/22 10.0.0.0/24 10.0.2.0/25",
+			"10.0.1.0-10.0.1.255 (256 addresses): 10.0.1.0/24\n10.0.2.128-10.0.3.255 (384 addresses): 10.0.2.128/25 10.0.3.0/24\n",
+			"10.0.1.0/24\n10.0.2.128/25\n10.0.3.0/24\n"},
+		{"histogram 10.0.0.0/24 10.0.1.0/24 10.1.0.0/16",
+			"/16  1  " + strings.Repeat("#", 26) + "\n/24  2  " + strings.Repeat("#", 52) + "\n",
+			"/16  1\n/24  2\n"},
+		{"mask-align 12.8.6.6",
+			"16.8.8\nchanged: boundary 12 -> 16, boundary 20 -> 24, boundary 26 -> 24 (a field was dropped)\n",
+			"16.8.8\n"},
+		{"mask-bits --mask 12.8.6.6 --within 172.16.0.0/12", "12 network, 20 host\n", "12 20\n"},
+		{"selftest --mask 12.8.6.6 --count 100", "100 roundtrips ok\n", ""},
+		{"validate-mask 12.8.6.6", "valid, prefix /12\n", "12\n"},
+	}
+
+	for _, tt := range tests {
+		args := strings.Fields(tt.args)
+		if got := runRoot(t, args...); got != tt.decorated {
+			t.Errorf("cidr %s printed %q, want %q", tt.args, got, tt.decorated)
+		}
+		if got := runRoot(t, append([]string{"--result-only"}, args...)...); got != tt.bare {
+			t.Errorf("cidr --result-only %s printed %q, want %q", tt.args, got, tt.bare)
+		}
+	}
+}
+
+func TestResultOnlyConfigNotice(t *testing.T) {
+	home := tempHome(t)
+	runRoot(t, "profile", "save", "prod", "--mask", "12.8.6.6", "--within", "172.16.0.0")
+
+	viper.Reset()
+	if got := runRoot(t, "--profile", "prod", "0.1.1.1"); !strings.HasPrefix(got, "Using config file: "+home) {
+		t.Errorf("without --result-only the config file wasn't noted: %q", got)
+	}
+	viper.Reset()
+	if got := runRoot(t, "--result-only", "--profile", "prod", "0.1.1.1"); got != "172.16.16.65\n" {
+		t.Errorf("--result-only printed %q", got)
+	}
+}
@@ -15,6 +15,9 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"math/bits"
+	"net/netip"
 	"os"
 	"strconv"
 	"strings"
@@ -24,9 +27,16 @@ import (
 	"github.com/spf13/viper"
 )
 
+// homeDir finds the directory searched for .cidr.yaml; a variable so
+// that a failed lookup can be simulated
+var homeDir = homedir.Dir
+
 var (
-	cfgFile         string
-	withinFieldMask []int = []int{8, 8, 8, 8}
+	cfgFile      string
+	noConfig     bool
+	strictConfig bool
+	debug        bool
+	resultOnly   bool
 )
 
 // RootCmd represents the base command when called without any subcommands
@@ -35,33 +45,204 @@ var RootCmd = &cobra.Command{
 	Short: "return a network address given a mask and a value",
 	Long: `Calculate a network 'address' give a mask and a value.  This is useful
 when dealing with the 172.16.0.0/12 CIDR or when subnets don't align
-with octet boundaries.  Example:
+with octet boundaries.  The bare command is the same as pack, and unpack
+reverses it.  Example:
 
 	cidr --mask 12.8.6.6 --within 172.16.0.0 0.1.1.1
 
 returns
 
 	172.16.16.65
+
+The mask lists each field's width in bits, separated by any non-digit,
+e.g. 12.8.6.6.  Fields may be named and limited to a range, e.g.
+12:region,8:pod[0-200],6:rack,6:host.  The widths may also be worked out
+as arithmetic: '+' separates the fields, and each field may use '*',
+'-' and parentheses, inside which '+' adds.  For example '3*8+6+2' is
+the mask 24.6.2, and '(2*8+4)+6+6' is 20.6.6.
 	`,
+	// the bare command takes the value as its argument, so it must not
+	// be mistaken for an unknown subcommand
+	Args: cobra.ArbitraryArgs,
 	// Uncomment the following line if your bare application
 	// has an action associated with it:
 	Run: func(cmd *cobra.Command, args []string) {
 
-		if len(args) != 1 {
-			cmd.Usage()
+		examples, err := cmd.Flags().GetBool("examples")
+		if err != nil {
+			panic(err)
+		}
+		if examples {
+			printExamples(os.Stdout)
 			return
 		}
 
-		mask, err := cmd.Flags().GetString("mask")
+		profile, err := cmd.Flags().GetString("profile")
 		if err != nil {
 			panic(err)
 		}
+		if len(profile) > 0 {
+			if err := applyProfile(cmd, profile); err != nil {
+				fmt.Printf("%s\n", err)
+				return
+			}
+		}
+
+		jsonInput, err := cmd.Flags().GetString("json-input")
+		if err != nil {
+			panic(err)
+		}
+
+		if len(jsonInput) > 0 && len(args) != 0 || len(jsonInput) == 0 && len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		mask, err := maskFromFlags(cmd)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			return
+		}
 		within, err := cmd.Flags().GetString("within")
 		if err != nil {
 			panic(err)
 		}
 
-		str, err := translate(args[0], mask, within)
+		var value string
+		if len(jsonInput) > 0 {
+			value, err = valueFromJSON(jsonInput, mask)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				return
+			}
+			logger.Debug("json input", "input", jsonInput, "value", value)
+		} else {
+			value = args[0]
+
+			class, err := cmd.Flags().GetString("separator-class")
+			if err != nil {
+				panic(err)
+			}
+			if err := checkSeparatorClass(value, class); err != nil {
+				fmt.Printf("%s\n", err)
+				return
+			}
+		}
+
+		opts, err := translateOptionsFromFlags(cmd, within)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			return
+		}
+		traceBits, err := cmd.Flags().GetBool("trace-bits")
+		if err != nil {
+			panic(err)
+		}
+		if traceBits {
+			opts.trace = os.Stderr
+		}
+
+		hints, err := cmd.Flags().GetBool("hints")
+		if err != nil {
+			panic(err)
+		}
+		if hints && looksSwapped(value, mask, opts.addrBits()) {
+			fmt.Fprintf(os.Stderr, "hint: did you swap --mask and the value? '%s' looks like a mask and '%s' doesn't\n", value, mask)
+		}
+
+		bits, err := cmd.Flags().GetInt("prefix")
+		if err != nil {
+			panic(err)
+		}
+
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+		tmpl, err := cmd.Flags().GetString("output-template")
+		if err != nil {
+			panic(err)
+		}
+		if len(tmpl) > 0 {
+			if output != "text" {
+				fmt.Printf("--output and --output-template may not be used together\n")
+				return
+			}
+			res, err := newResult(value, mask, within, bits, opts)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				return
+			}
+			if err := writeTemplate(os.Stdout, res, tmpl); err != nil {
+				fmt.Printf("%s\n", err)
+			}
+			return
+		}
+		if output != "text" {
+			res, err := newResult(value, mask, within, bits, opts)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				return
+			}
+			if err := writeEncoded(os.Stdout, res, output); err != nil {
+				fmt.Printf("%s\n", err)
+			}
+			return
+		}
+
+		explain, err := cmd.Flags().GetBool("explain")
+		if err != nil {
+			panic(err)
+		}
+		if explain && !resultOnly {
+			fields, values, names, err := packFields(value, mask, opts)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				return
+			}
+			explainFields(os.Stdout, fields, values, names)
+		}
+
+		asInteger, err := cmd.Flags().GetBool("integer")
+		if err != nil {
+			panic(err)
+		}
+		base, err := cmd.Flags().GetInt("output-base")
+		if err != nil {
+			panic(err)
+		}
+		if asInteger {
+			if bits >= 0 {
+				fmt.Printf("--integer and --prefix may not be used together\n")
+				return
+			}
+			if base < 2 || base > 36 {
+				fmt.Printf("invalid output base %d, expected 2-36\n", base)
+				return
+			}
+			addr, err := translateAddr(value, mask, within, opts)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				return
+			}
+			if opts.littleEndian {
+				addr = swapBytes(addr)
+			}
+			fmt.Printf("%s\n", formatInteger(addr, base))
+			return
+		}
+
+		if bits >= 0 {
+			prefix, err := translatePrefix(value, mask, within, bits, opts)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				return
+			}
+			fmt.Printf("%s\n", formatPrefix(prefix, opts))
+			return
+		}
+
+		str, err := translate(value, mask, within, opts)
 		if err != nil {
 			fmt.Printf("%s\n", err)
 			return
@@ -71,47 +252,290 @@ returns
 	},
 }
 
-// translate the inputs into a network value
-func translate(value, mask, within string) (string, error) {
+// the mask selected by --mask, --mask-file, --derive-mask or
+// --mask-from-prefix, and --mask-type
+func maskFromFlags(cmd *cobra.Command) (string, error) {
+	mask, err := cmd.Flags().GetString("mask")
+	if err != nil {
+		panic(err)
+	}
+	mask = stripMaskComment(mask)
+	netmask, err := cmd.Flags().GetString("derive-mask")
+	if err != nil {
+		panic(err)
+	}
+	maskPrefix, err := cmd.Flags().GetInt("mask-from-prefix")
+	if err != nil {
+		panic(err)
+	}
+	maskType, err := cmd.Flags().GetString("mask-type")
+	if err != nil {
+		panic(err)
+	}
+	maskFile, err := cmd.Flags().GetString("mask-file")
+	if err != nil {
+		panic(err)
+	}
+	if countChanged(cmd, maskFlags...) > 1 {
+		return "", fmt.Errorf("only one of --mask, --mask-file, --derive-mask and --mask-from-prefix may be used")
+	}
+	if len(maskFile) > 0 {
+		mask, err = readMaskFile(maskFile)
+		if err != nil {
+			return "", err
+		}
+		logger.Info("read mask", "file", maskFile, "mask", mask)
+	}
+	switch maskType {
+	case "widths":
+	case "netmask":
+		netmask = mask
+	default:
+		return "", fmt.Errorf("unknown mask type '%s', expected widths or netmask", maskType)
+	}
+	if len(netmask) > 0 {
+		mask, err = deriveMask(netmask)
+		if err != nil {
+			return "", err
+		}
+		logger.Info("derived mask", "netmask", netmask, "mask", mask)
+	}
+	if cmd.Flags().Changed("mask-from-prefix") {
+		mask, err = maskFromPrefix(maskPrefix)
+		if err != nil {
+			return "", err
+		}
+		logger.Info("derived mask", "prefix", maskPrefix, "mask", mask)
+	}
 
-	//parse the mask
-	fields, err := parse(mask)
+	return mask, nil
+}
+
+// the translateOptions given by the flags shared by pack and unpack.
+// value and within decide the family when --family is auto.
+func translateOptionsFromFlags(cmd *cobra.Command, within string) (translateOptions, error) {
+	allowShort, err := cmd.Flags().GetBool("allow-short-value")
 	if err != nil {
-		return "", err
+		panic(err)
+	}
+	pad, err := cmd.Flags().GetString("pad")
+	if err != nil {
+		panic(err)
+	}
+	if pad != "low" && pad != "high" {
+		return translateOptions{}, fmt.Errorf("unknown padding '%s', expected low or high", pad)
 	}
 
-	// make sure the mask sums to 32
-	sum := 0
-	for _, i := range fields {
-		sum += i
+	order, err := cmd.Flags().GetString("field-order")
+	if err != nil {
+		panic(err)
 	}
-	if sum != 32 {
-		return "", fmt.Errorf("expected the mask to define 32 bits, only found %d", sum)
+	lsbFirst, err := parseFieldOrder(order)
+	if err != nil {
+		return translateOptions{}, err
 	}
 
-	// parse the value
-	values, err := parse(value)
+	verify, err := cmd.Flags().GetBool("verify")
+	if err != nil {
+		panic(err)
+	}
 
-	if len(fields) != len(values) {
-		return "",
-			fmt.Errorf("different number of fields in the mask(%d) and the value(%d)", len(fields), len(values))
+	name, err := cmd.Flags().GetString("family")
+	if err != nil {
+		panic(err)
+	}
+	family, err := parseFamily(name, within)
+	if err != nil {
+		return translateOptions{}, err
 	}
 
-	netmask, err := computeCIDR(fields, values)
+	uppercase, err := cmd.Flags().GetBool("uppercase")
 	if err != nil {
-		return "", err
+		panic(err)
+	}
+	abbreviate, err := cmd.Flags().GetString("abbreviate")
+	if err != nil {
+		panic(err)
+	}
+	if abbreviate != "compressed" && abbreviate != "full" {
+		return translateOptions{}, fmt.Errorf("unknown abbreviation '%s', expected full or compressed", abbreviate)
+	}
+	delim, err := cmd.Flags().GetString("prefix-delimiter")
+	if err != nil {
+		panic(err)
+	}
+	integerInput, err := cmd.Flags().GetBool("integer-input")
+	if err != nil {
+		panic(err)
+	}
+	endianness, err := cmd.Flags().GetString("endianness")
+	if err != nil {
+		panic(err)
+	}
+	littleEndian, err := parseEndianness(endianness)
+	if err != nil {
+		return translateOptions{}, err
+	}
+	lenient, err := cmd.Flags().GetBool("lenient")
+	if err != nil {
+		panic(err)
+	}
+	unit, err := cmd.Flags().GetString("width-unit")
+	if err != nil {
+		panic(err)
+	}
+	widthUnit, err := parseWidthUnit(unit)
+	if err != nil {
+		return translateOptions{}, err
+	}
+	fieldBase, err := cmd.Flags().GetInt("field-base")
+	if err != nil {
+		panic(err)
+	}
+	if fieldBase < 2 || fieldBase > 36 {
+		return translateOptions{}, fmt.Errorf("invalid field base %d, expected 2-36", fieldBase)
 	}
 
-	withinValues, err := parse(within)
-	if len(withinFieldMask) != len(withinValues) {
-		return "",
-			fmt.Errorf("different number of fields in the mask(%d) and the value(%d)",
-				len(withinFieldMask), len(withinValues))
+	return translateOptions{
+		padShort:        allowShort,
+		padHigh:         pad == "high",
+		lsbFirst:        lsbFirst,
+		verify:          verify,
+		family:          family,
+		uppercase:       uppercase,
+		expand:          abbreviate == "full",
+		integerInput:    integerInput,
+		littleEndian:    littleEndian,
+		fieldBase:       fieldBase,
+		widthUnit:       widthUnit,
+		lenient:         lenient,
+		prefixDelimiter: delim,
+	}, nil
+}
+
+// the flags which each select the mask; only one may be used
+var maskFlags = []string{"mask", "mask-file", "derive-mask", "mask-from-prefix"}
+
+// true if the value's fields look like the widths of a mask for a bits
+// wide address and the mask's fields don't, as when the two are swapped
+func looksSwapped(value, mask string, bits int) bool {
+	widths, err := parse(value)
+	if err != nil || sumFields(widths) != bits {
+		return false
+	}
+	for _, w := range widths {
+		if w < 1 || w > bits {
+			return false
+		}
 	}
-	withinCIDR, err := computeCIDR(withinFieldMask, withinValues)
 
-	for i, x := range withinCIDR {
-		netmask[i] = netmask[i] | x
+	fields, _, err := parseNamedMaskBits(mask, 0, 1)
+	return err != nil || sumFields(fields) != bits
+}
+
+// the number of the named flags which were set on the command line
+func countChanged(cmd *cobra.Command, names ...string) int {
+	n := 0
+	for _, name := range names {
+		if cmd.Flags().Changed(name) {
+			n++
+		}
+	}
+	return n
+}
+
+// options which alter how translate interprets its inputs
+type translateOptions struct {
+	// pad a value with fewer fields than the mask with zero fields
+	// (--allow-short-value).  otherwise the counts must match exactly.
+	padShort bool
+
+	// pad a short value's leading (high order) fields rather than its
+	// trailing (low order) fields, so the value supplies the last fields
+	padHigh bool
+
+	// shift the fields in least-significant first (--field-order lsb)
+	lsbFirst bool
+
+	// recompute the result independently of computeCIDR, and check with
+	// net.ParseCIDR that it lies inside a within's prefix
+	verify bool
+
+	// the address family, 4 or 6.  zero is treated as 4.
+	family int
+
+	// the field widths used to parse the within; nil means 8.8.8.8
+	withinMask []int
+
+	// render IPv6 results in uppercase hex
+	uppercase bool
+
+	// render IPv6 results in full, without :: compression
+	expand bool
+
+	// separates the address from the prefix length in CIDR output;
+	// empty means "/"
+	prefixDelimiter string
+
+	// accept a value with no separator as a raw 32 bit integer address
+	integerInput bool
+
+	// read and write integers least significant byte first
+	// (--endianness little)
+	littleEndian bool
+
+	// the base of the value's fields; zero means decimal (with 0x hex)
+	fieldBase int
+
+	// the number of bits in each unit of a mask field's width, 8 for
+	// --width-unit bytes.  zero is treated as 1.
+	widthUnit int
+
+	// accept a mask whose widths don't sum to the address width,
+	// logging a warning instead of failing
+	lenient bool
+
+	// write the packing's accumulator after each field is shifted in
+	// (--trace-bits); nil for no trace
+	trace io.Writer
+
+	// packs the value's fields into octets; nil means computeCIDR.  a
+	// test sets it to break the packing and check that --verify catches it.
+	pack func(fields, values []int, lsbFirst bool, trace io.Writer) ([]int, error)
+}
+
+// the number of bits in an address of the selected family
+func (opts translateOptions) addrBits() int {
+	if opts.family == 6 {
+		return 128
+	}
+	return 32
+}
+
+// Translate packs the value into the mask's fields, ORs in the within,
+// and returns the dotted result.  It reads no mutable package state,
+// taking its settings from its arguments alone, and is safe for
+// concurrent use, even while the log level changes.
+func Translate(value, mask, within string) (string, error) {
+	return translate(value, mask, within, translateOptions{})
+}
+
+// translate the inputs into a network value
+func translate(value, mask, within string, opts translateOptions) (string, error) {
+
+	logger.Debug("translate", "value", value, "mask", mask, "within", within)
+
+	if opts.family == 6 {
+		addr, err := pack6(value, mask, within, opts)
+		if err != nil {
+			return "", err
+		}
+		return formatAddr(addr, opts), nil
+	}
+
+	netmask, err := pack(value, mask, within, opts)
+	if err != nil {
+		return "", err
 	}
 
 	var output string
@@ -124,13 +548,385 @@ func translate(value, mask, within string) (string, error) {
 	return output, nil
 }
 
+// pack the value into the mask's fields and OR in the within,
+// returning the four octets of the resulting address
+func pack(value, mask, within string, opts translateOptions) ([]int, error) {
+
+	fields, values, _, err := packFields(value, mask, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.trace != nil {
+		fmt.Fprintf(opts.trace, "value %s:\n", value)
+	}
+	pack := opts.pack
+	if pack == nil {
+		pack = computeCIDR
+	}
+	netmask, err := pack(fields, values, opts.lsbFirst, opts.trace)
+	if err != nil {
+		return nil, err
+	}
+
+	within, fixed, err := splitWithin(within, 32)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFixedBits(ipv4ToAddr(octetsToIPv4(netmask)), within, fixed); err != nil {
+		return nil, err
+	}
+
+	withinCIDR, err := parseWithin(within, opts.withinMask, opts.trace)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, x := range withinCIDR {
+		netmask[i] = netmask[i] | x
+	}
+	logger.Debug("combined with within", "within", withinCIDR, "result", netmask)
+
+	if opts.verify {
+		if err := verifyPacking(netmask, fields, values, within, opts); err != nil {
+			return nil, err
+		}
+		if err := verifyResult(netmask, withinCIDR, fixed); err != nil {
+			return nil, err
+		}
+	}
+
+	return netmask, nil
+}
+
+// split a within such as 172.16.0.0/12 into its address and the number
+// of leading bits it fixes, which is -1 when no prefix is given
+func splitWithin(within string, bits int) (string, int, error) {
+	i := strings.IndexByte(within, '/')
+	if i < 0 {
+		return within, -1, nil
+	}
+
+	fixed, err := strconv.Atoi(within[i+1:])
+	if err != nil || fixed < 0 || fixed > bits {
+		return "", 0, fmt.Errorf("'%s' has an invalid prefix length, expected 0-%d", within, bits)
+	}
+	return within[:i], fixed, nil
+}
+
+// the number of leading bits a within fixes, the one rule shared by
+// prefix-of, mask-bits and hostmask: an explicit /N wins, otherwise
+// every field of withinFieldMask (octets when nil) up to and including
+// the last non-zero one, so 172.16.0.0 fixes 16 bits.  packing only
+// enforces an explicit prefix; a bare within is simply OR'ed in.
+func withinPrefix(within string, withinFieldMask []int) (int, error) {
+	addr, explicit, err := splitWithin(within, 32)
+	if err != nil {
+		return 0, err
+	}
+	if withinFieldMask == nil {
+		withinFieldMask = []int{8, 8, 8, 8}
+	}
+
+	values, err := parse(addr)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkFieldCounts(len(withinFieldMask), len(values)); err != nil {
+		return 0, err
+	}
+	// make sure each value fits in its field
+	if _, err := computeCIDR(withinFieldMask, values, false, nil); err != nil {
+		return 0, err
+	}
+	if explicit >= 0 {
+		return explicit, nil
+	}
+
+	bits, fixed := 0, 0
+	for i, f := range withinFieldMask {
+		bits += f
+		if values[i] != 0 {
+			fixed = bits
+		}
+	}
+	return fixed, nil
+}
+
+// make sure the packed value leaves the within's fixed bits alone
+func checkFixedBits(value netip.Addr, within string, fixed int) error {
+	if fixed <= 0 {
+		return nil
+	}
+	if network, _ := value.Prefix(fixed); !network.Addr().IsUnspecified() {
+		return fmt.Errorf("the value sets bits in the first %d bits, which are fixed by the within %s/%d",
+			fixed, within, fixed)
+	}
+	return nil
+}
+
+// parse the within into its four octets.  a nil withinFieldMask
+// splits the within into octets.  a non-nil trace traces the packing.
+func parseWithin(within string, withinFieldMask []int, trace io.Writer) ([]int, error) {
+	if withinFieldMask == nil {
+		withinFieldMask = []int{8, 8, 8, 8}
+	}
+
+	withinValues, err := parse(within)
+	if err != nil {
+		return nil, err
+	}
+	if len(withinFieldMask) != len(withinValues) {
+		return nil,
+			fmt.Errorf("different number of fields in the mask(%d) and the value(%d)",
+				len(withinFieldMask), len(withinValues))
+	}
+	if trace != nil {
+		fmt.Fprintf(trace, "within %s:\n", within)
+	}
+	return computeCIDR(withinFieldMask, withinValues, false, trace)
+}
+
+// parse the mask and the value, returning the field widths, the
+// value for each field, and the (possibly empty) name of each field
+func packFields(value, mask string, opts translateOptions) ([]int, []int, []string, error) {
+
+	//parse the mask
+	bits := opts.addrBits()
+	if opts.lenient {
+		bits = 0
+	}
+	fields, names, err := parseNamedMaskBits(mask, bits, opts.widthUnit)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if opts.lenient {
+		if sum := sumFields(fields); sum != opts.addrBits() {
+			logger.Warn("lenient mask does not match the address width; packing into the mask's own width",
+				"mask", mask, "bits", sum, "expected", opts.addrBits())
+		}
+	}
+
+	// parse the value
+	var values []int
+	if opts.integerInput && isInteger(value) {
+		values, err = integerValues(value, fields, opts)
+	} else if base := fieldBaseOf(opts); opts.padShort && isLoneField(value, base) {
+		// a lone field, which padding turns into a full value
+		var v int64
+		v, err = strconv.ParseInt(value, base, 0)
+		if err != nil {
+			err = fmt.Errorf("error parsing base %d field '%s' -- %s", base, value, err)
+		}
+		values = []int{int(v)}
+	} else {
+		values, err = parseBase(value, opts.fieldBase)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if len(fields) > len(values) && opts.padShort {
+		zeros := make([]int, len(fields)-len(values))
+		if opts.padHigh {
+			values = append(zeros, values...)
+		} else {
+			values = append(values, zeros...)
+		}
+	}
+
+	if err := checkFieldCounts(len(fields), len(values)); err != nil {
+		return nil, nil, nil, err
+	}
+
+	// a named mask may limit the values of its fields beyond their width
+	ranges, err := parseFieldRanges(stripMaskComment(mask))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if err := checkFieldRanges(values, names, ranges); err != nil {
+		return nil, nil, nil, err
+	}
+
+	return fields, values, names, nil
+}
+
+// true if s is made up only of decimal digits
+func isInteger(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// split a raw integer address, e.g. 2886795333, into the value of each
+// of the mask's fields
+func integerValues(value string, fields []int, opts translateOptions) ([]int, error) {
+	if opts.addrBits() != 32 {
+		return nil, fmt.Errorf("--integer-input only supports IPv4 addresses")
+	}
+	addr, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("'%s' is not a valid 32 bit integer address", value)
+	}
+	if opts.littleEndian {
+		addr = uint64(bits.ReverseBytes32(uint32(addr)))
+	}
+	logger.Debug("integer input", "value", value, "address", formatIPv4(uint32(addr)))
+
+	if !opts.lsbFirst {
+		return unpackFields(uint32(addr), fields), nil
+	}
+
+	// the last field is packed into the most significant bits
+	reversed := make([]int, len(fields))
+	for i, f := range fields {
+		reversed[len(fields)-i-1] = f
+	}
+	values := unpackFields(uint32(addr), reversed)
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+	return values, nil
+}
+
+// convert a --width-unit name into the number of bits per unit
+func parseWidthUnit(unit string) (int, error) {
+	switch unit {
+	case "bits":
+		return 1, nil
+	case "bytes":
+		return 8, nil
+	}
+	return 0, fmt.Errorf("unknown width unit '%s', expected bits or bytes", unit)
+}
+
+// convert an --endianness name, returning true for little
+func parseEndianness(name string) (bool, error) {
+	switch name {
+	case "big":
+		return false, nil
+	case "little":
+		return true, nil
+	}
+	return false, fmt.Errorf("unknown endianness '%s', expected big or little", name)
+}
+
+// convert a --field-order name, returning true for lsb
+func parseFieldOrder(order string) (bool, error) {
+	switch order {
+	case "msb":
+		return false, nil
+	case "lsb":
+		return true, nil
+	}
+	return false, fmt.Errorf("unknown field order '%s', expected msb or lsb", order)
+}
+
+// parse a mask and make sure its fields sum to 32 bits
+func parseMask(mask string) ([]int, error) {
+	fields, _, err := parseNamedMask(mask)
+	return fields, err
+}
+
+// parseMask, also returning the name of each field.  fields without a
+// name (including every field of an unnamed mask) have an empty name.
+func parseNamedMask(mask string) ([]int, []string, error) {
+	return parseNamedMaskBits(mask, 32, 1)
+}
+
+// parseNamedMask for an address of the given number of bits, or of any
+// number of bits if bits is 0.  each field's width is multiplied by
+// unit, e.g. 8 for widths in bytes.
+func parseNamedMaskBits(mask string, bits, unit int) ([]int, []string, error) {
+	var fields []int
+	var names []string
+	var err error
+
+	mask = stripMaskComment(mask)
+	if isNamedMask(mask) {
+		fields, names, err = parseFieldNames(mask)
+	} else if isMaskExpression(mask) {
+		fields, err = parseMaskExpression(mask)
+		names = make([]string, len(fields))
+	} else {
+		fields, err = parse(mask)
+		names = make([]string, len(fields))
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if unit > 1 {
+		for i := range fields {
+			fields[i] *= unit
+		}
+	}
+
+	sum := sumFields(fields)
+	if bits > 0 && sum != bits {
+		diff, amount := sum-bits, "too many"
+		if diff < 0 {
+			diff, amount = -diff, "too few"
+		}
+		err := fmt.Errorf("mask defines %d bits, expected %d (%d %s)", sum, bits, diff, amount)
+		if bits == 32 && looksLikeNetmask(fields) {
+			err = fmt.Errorf("%s; did you mean a netmask? use --mask-type netmask", err)
+		}
+		return nil, nil, err
+	}
+
+	return fields, names, nil
+}
+
+// the total width of the fields
+func sumFields(fields []int) int {
+	sum := 0
+	for _, f := range fields {
+		sum += f
+	}
+	return sum
+}
+
+// explain a mismatch between the number of mask fields and value fields
+func checkFieldCounts(maskFields, valueFields int) error {
+	switch {
+	case maskFields > valueFields:
+		return fmt.Errorf("mask defines %d fields but value only provides %d; pad the value or trim the mask",
+			maskFields, valueFields)
+	case maskFields < valueFields:
+		return fmt.Errorf("value provides %d fields but mask only defines %d; trim the value or extend the mask",
+			valueFields, maskFields)
+	}
+	return nil
+}
+
 // parse a dotted set of integers into an an array of ints
-// any non-numeric may be used as the separator
+// any non-numeric may be used as the separator.  fields may be
+// given in hex with a 0x prefix, in either case.
 func parse(mask string) ([]int, error) {
 	var sep string
 
-	for _, c := range mask {
-		if c < '0' || c > '9' {
+	if err := checkASCII(mask); err != nil {
+		return nil, err
+	}
+
+	// the separator is the first character which can't belong to the
+	// first field
+	first := mask
+	isDigit := func(c rune) bool { return c >= '0' && c <= '9' }
+	if isHexField(mask) {
+		first = mask[2:]
+		isDigit = func(c rune) bool { return strings.ContainsRune("0123456789abcdefABCDEF", c) }
+	}
+	pos := len(mask) - len(first)
+	for _, c := range first {
+		pos++
+		if !isDigit(c) {
 			sep = string(c)
 			break
 		}
@@ -139,36 +935,239 @@ func parse(mask string) ([]int, error) {
 	if len(sep) == 0 {
 		return nil, fmt.Errorf("The mask '%s' has only one or no fields", mask)
 	}
+	// a letter is a stray digit of another base rather than a separator,
+	// so 'ff.0.0.0' isn't reported as empty fields split on 'f'
+	if c := sep[0]; c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' {
+		return nil, fmt.Errorf("unexpected character '%c' at position %d in '%s'; fields are decimal numbers, or hex with a 0x prefix",
+			c, pos, mask)
+	}
 
 	str := strings.Split(mask, sep)
+	if err := checkEmptyFields(mask, sep, str); err != nil {
+		return nil, err
+	}
+	if err := checkFieldChars(mask, sep, str); err != nil {
+		return nil, err
+	}
 	fields := make([]int, len(str))
 
 	for i, s := range str {
 		var err error
-		fields[i], err = strconv.Atoi(s)
+		fields[i], err = parseField(s)
 		if err != nil {
 			return nil, fmt.Errorf("error parsing mask field '%s' -- %s", s, err)
 		}
 	}
+	logger.Debug("parse", "input", mask, "separator", sep, "fields", fields)
 
 	return fields, nil
 }
 
-// return 4 ints based on the fields & values provided
-func computeCIDR(fields, values []int) ([]int, error) {
+// reject characters which can't appear in a mask or value, such as a
+// smart quote or non-breaking space pasted in from a document, before
+// one of them is mistaken for the separator
+func checkASCII(input string) error {
+	pos := 0
+	for _, c := range input {
+		pos++
+		if c > '~' || c < ' ' {
+			return fmt.Errorf("unexpected character %#U at position %d in '%s'; only ASCII digits and a separator are allowed",
+				c, pos, input)
+		}
+	}
+	return nil
+}
 
-	var result uint32
+// the separator each --separator-class allows; any allows every one
+var separatorClasses = map[string]string{
+	"dot":   ".",
+	"colon": ":",
+	"dash":  "-",
+	"any":   "",
+}
+
+// reject a value separated by anything but the class's separator, so
+// a mistyped separator isn't silently accepted, e.g. 1:2:3:4 for dot
+func checkSeparatorClass(value, class string) error {
+	sep, ok := separatorClasses[class]
+	if !ok {
+		return fmt.Errorf("unknown separator class '%s', expected dot, colon, dash or any", class)
+	}
+	if len(sep) == 0 {
+		return nil
+	}
+
+	// letters are digits of a higher --field-base, or a 0x prefix
+	for i, c := range value {
+		isDigit := c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+		if !isDigit && string(c) != sep {
+			return fmt.Errorf("unexpected separator '%c' at position %d in '%s'; --separator-class %s allows only '%s'",
+				c, i+1, value, class, sep)
+		}
+	}
+	return nil
+}
+
+// report the first character of a field which is neither a digit nor,
+// in a 0x prefixed field, a hex digit, e.g. a second kind of separator
+func checkFieldChars(input, sep string, fields []string) error {
+	pos := 0
+	for _, f := range fields {
+		digits := "0123456789"
+		skip := 0
+		if isHexField(f) {
+			digits, skip = "0123456789abcdefABCDEF", 2
+		}
+		for i, c := range f {
+			if i >= skip && !strings.ContainsRune(digits, c) {
+				return fmt.Errorf("unexpected character '%c' at position %d in '%s'; fields are separated by '%s'",
+					c, pos+i+1, input, sep)
+			}
+		}
+		pos += len(f) + len(sep)
+	}
+	return nil
+}
+
+// explain an empty field left by a leading, trailing or doubled
+// separator, e.g. 172.16.0.0.
+func checkEmptyFields(input, sep string, fields []string) error {
 	for i, f := range fields {
+		if len(f) > 0 {
+			continue
+		}
+		switch i {
+		case 0:
+			return fmt.Errorf("'%s' has an empty leading field; remove the leading '%s'", input, sep)
+		case len(fields) - 1:
+			return fmt.Errorf("'%s' has an empty trailing field; remove the trailing '%s'", input, sep)
+		}
+		return fmt.Errorf("'%s' has an empty field #%d; remove the repeated '%s'", input, i, sep)
+	}
+	return nil
+}
+
+// parse a value whose fields are written in the given base, e.g.
+// ff.0.0.0 in base 16.  the separator is the first character which
+// isn't a digit of the base.  base 0 or 10 is the same as parse.
+func parseBase(value string, base int) ([]int, error) {
+	if base == 0 || base == 10 {
+		return parse(value)
+	}
+	if err := checkASCII(value); err != nil {
+		return nil, err
+	}
+
+	sep := ""
+	for _, c := range value {
+		if digitValue(c) >= base {
+			sep = string(c)
+			break
+		}
+	}
+	if len(sep) == 0 {
+		return nil, fmt.Errorf("The value '%s' has only one or no fields", value)
+	}
+
+	str := strings.Split(value, sep)
+	if err := checkEmptyFields(value, sep, str); err != nil {
+		return nil, err
+	}
+	fields := make([]int, len(str))
+	for i, s := range str {
+		v, err := strconv.ParseInt(s, base, 0)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing base %d field '%s' -- %s", base, s, err)
+		}
+		fields[i] = int(v)
+	}
+	logger.Debug("parse", "input", value, "base", base, "separator", sep, "fields", fields)
+
+	return fields, nil
+}
+
+// the base of the value's fields, with zero meaning decimal
+func fieldBaseOf(opts translateOptions) int {
+	if opts.fieldBase == 0 {
+		return 10
+	}
+	return opts.fieldBase
+}
+
+// true if s is a single field of digits in the base, with no separator
+func isLoneField(s string, base int) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for _, c := range s {
+		if digitValue(c) >= base {
+			return false
+		}
+	}
+	return true
+}
+
+// the value of c as a digit in bases up to 36, or 36 if it isn't one
+func digitValue(c rune) int {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'z':
+		return int(c-'a') + 10
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 10
+	}
+	return 36
+}
+
+// true if the field starts with a 0x (or 0X) hex prefix
+func isHexField(s string) bool {
+	return len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X')
+}
+
+// parse a single decimal or 0x prefixed hex field
+func parseField(s string) (int, error) {
+	if isHexField(s) {
+		v, err := strconv.ParseInt(s[2:], 16, 0)
+		return int(v), err
+	}
+	return strconv.Atoi(s)
+}
+
+// return 4 ints based on the fields & values provided.  fields are
+// shifted in most-significant first unless lsbFirst is set, in which
+// case the last field lands in the most significant bits.  a non-nil
+// trace gets the result after each field is shifted in.
+func computeCIDR(fields, values []int, lsbFirst bool, trace io.Writer) ([]int, error) {
+
+	var result uint32
+	for n := range fields {
+		i := n
+		if lsbFirst {
+			i = len(fields) - n - 1
+		}
+		f := fields[i]
+
 		var field uint32 = uint32(f)
 		var uval uint32 = uint32(values[i])
 		field = uval & generateAndMask(f)
 		if field != uval {
-			return nil, fmt.Errorf("field #%d (%d) exceeds the defined field length of %d", i, uval, f)
+			return nil, fmt.Errorf("field #%d (%d) exceeds max %d for width %d", i, uval, generateAndMask(f), f)
 		}
 
-		result = result << uint32(f)
-		result = result | field
+		// a lenient mask may define more than 32 bits, but its leading
+		// bits must be zero
+		wide := uint64(result)<<uint(f) | uint64(field)
+		if wide>>32 != 0 {
+			return nil, fmt.Errorf("the value does not fit in a 32 bit address; the leading %d bits of the mask must be zero", sumFields(fields)-32)
+		}
+		result = uint32(wide)
+
+		if trace != nil {
+			fmt.Fprintf(trace, "  field #%d (%d bits): 0x%08x\n", i, f, result)
+		}
 	}
+	logger.Debug("computeCIDR", "fields", fields, "values", values, "result", fmt.Sprintf("0x%08x", result))
 
 	netmask := make([]int, 4)
 	for i, _ := range netmask {
@@ -180,22 +1179,37 @@ func computeCIDR(fields, values []int) ([]int, error) {
 	return netmask, nil
 }
 
-// generate a bitmask of 1's of the specified length
-// (this seems overly brute force?)
-func generateAndMask(length int) uint32 {
-	var mask uint32
-
-	mask = 0
-	for i := 0; i < length; i++ {
-		mask <<= 1
-		mask |= 1
+// maskTable[n] is a bitmask of n 1's, for each field width 0-32,
+// computed once rather than for every field packed
+var maskTable = func() [33]uint32 {
+	var table [33]uint32
+	for n := 1; n < len(table); n++ {
+		table[n] = table[n-1]<<1 | 1
 	}
-	return mask
+	return table
+}()
+
+// generate a bitmask of 1's of the specified length.  a lenient mask's
+// field may be wider than 32 bits, which is all 32 bits.
+func generateAndMask(length int) uint32 {
+	return maskTable[min(max(length, 0), 32)]
 }
 
 // Execute adds all child commands to the root command sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
+	// report a panic as an error rather than a stack trace, unless
+	// --debug asks for the trace
+	defer func() {
+		if debug {
+			return
+		}
+		if r := recover(); r != nil {
+			fmt.Fprintf(os.Stderr, "internal error: %v (rerun with --debug for a stack trace)\n", r)
+			os.Exit(2)
+		}
+	}()
+
 	if err := RootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -203,28 +1217,81 @@ func Execute() {
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
+	cobra.OnInitialize(initLogging, initConfig)
 
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
 	// will be global for your application.
 	RootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cidr.yaml)")
+	RootCmd.PersistentFlags().BoolVar(&strictConfig, "strict-config", false, "fail if the config file can't be read or parsed")
+	RootCmd.PersistentFlags().BoolVar(&noConfig, "no-config", false, "ignore config files and environment variables")
+	RootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "let internal errors panic with a stack trace")
+	RootCmd.PersistentFlags().BoolVar(&resultOnly, "result-only", false, "print nothing but the result: no headers, labels or notices")
+	RootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "warn", "log level: debug, info, warn or error")
+
+	RootCmd.Flags().StringP("mask", "m", "8:13:4:7", "bitmask for translation (fields may be named, e.g. 12:region,8:pod,6:rack,6:host, limited to a range, e.g. 8:pod[0-200], or written as arithmetic, e.g. '3*8+6+2')")
+	RootCmd.Flags().String("mask-file", "", "read the bitmask from this file instead of --mask")
+	RootCmd.Flags().Bool("lenient", false, "warn rather than fail when the mask doesn't sum to 32 bits; a short mask fills only the low bits and an over-long one must leave its leading bits zero")
+	RootCmd.Flags().String("width-unit", "bits", "the unit of the mask's field widths: bits, or bytes (so 1.1.1.1 is four octets)")
+	RootCmd.Flags().String("mask-type", "widths", "how to read --mask: as field widths, or as a netmask to derive them from")
+	RootCmd.Flags().String("derive-mask", "", "derive the bitmask from a netmask such as 255.255.240.0")
+	RootCmd.Flags().Int("mask-from-prefix", -1, "derive the bitmask from a prefix length, e.g. 24 for 8.8.8.8")
+	RootCmd.Flags().StringP("within", "w", "0.0.0.0", "result is OR'ed with this CIDR; a /prefix fixes its leading bits (an IPv6 within selects IPv6)")
+	RootCmd.Flags().Int("prefix", -1, "print the result in CIDR form with this prefix length")
+	RootCmd.Flags().String("prefix-delimiter", "/", "separate the address and prefix length of CIDR output with this, e.g. ' '")
+	RootCmd.Flags().String("field-order", "msb", "pack the first field into the most (msb) or least (lsb) significant bits")
+	RootCmd.Flags().String("output-template", "", "format the result with a Go template, e.g. '{{.Address}} ({{.Integer}})'")
+	RootCmd.Flags().String("json-input", "", `supply the value as a JSON object keyed by named mask fields, e.g. '{"region":0,"pod":1}'`)
+	RootCmd.Flags().String("profile", "", "use the flags saved as this profile by 'cidr profile save'")
+	RootCmd.Flags().Bool("examples", false, "print worked examples and exit")
+	RootCmd.Flags().StringP("output", "o", "text", "output format: text, json or yaml")
+	RootCmd.Flags().Bool("integer", false, "print the result as a single integer")
+	RootCmd.Flags().Int("output-base", 10, "the base (2-36) of --integer output; 2, 8 and 16 are prefixed 0b, 0o and 0x")
+	RootCmd.Flags().Bool("hints", false, "print hints about likely mistakes, such as a swapped mask and value, to stderr")
+	RootCmd.Flags().Bool("trace-bits", false, "print the 32 bit result to stderr, in hex, after each field is shifted in")
+	RootCmd.Flags().Bool("explain", false, "describe each field's width and value before the result")
+	RootCmd.Flags().String("family", "auto", "address family: auto, 4 or 6")
+	RootCmd.Flags().Bool("uppercase", false, "render IPv6 results in uppercase hex")
+	RootCmd.Flags().String("abbreviate", "compressed", "render IPv6 results compressed with :: (RFC 5952) or in full")
+	RootCmd.Flags().Bool("verify", false, "recompute an IPv4 result independently and check it lies inside the within's /prefix with net.ParseCIDR")
+	RootCmd.Flags().Bool("allow-short-value", false, "pad a value with fewer fields than the mask with zeros, see --pad")
+	RootCmd.Flags().String("separator-class", "any", "the separator the value may use: dot, colon, dash, or any non-digit")
+	RootCmd.Flags().Bool("integer-input", false, "accept a value with no separator as a 32 bit integer address, e.g. 2886795333")
+	RootCmd.Flags().String("endianness", "big", "the byte order of --integer-input and --integer values: big (network order) or little")
+	RootCmd.Flags().Int("field-base", 10, "the base (2-36) of the value's fields, e.g. 16 to read ff.0.0.0 as hex")
+	RootCmd.Flags().String("pad", "low", "which fields of a short value are zero: low (trailing) or high (leading)")
 
-	RootCmd.Flags().StringP("mask", "m", "8:13:4:7", "bitmask for translation")
-	RootCmd.Flags().StringP("within", "w", "0.0.0.0", "result is OR'ed with this CIDR")
+	// pack is the bare command under its own name, so it shares every
+	// flag.  this must happen here: pack.go's init runs before this one.
+	packCmd.Flags().AddFlagSet(RootCmd.Flags())
+
+	// and unpack takes the same flags, so any pack can be reversed
+	decomposeCmd.Flags().AddFlagSet(RootCmd.Flags())
+
+	// and process and watch pack each row with them
+	processCmd.Flags().AddFlagSet(RootCmd.Flags())
+	watchCmd.Flags().AddFlagSet(RootCmd.Flags())
 }
 
 // initConfig reads in config file and ENV variables if set.
+// cobra parses the flags before running the OnInitialize hooks,
+// so --no-config is already known here.
 func initConfig() {
+	if noConfig {
+		return
+	}
+
 	if cfgFile != "" {
 		// Use config file from the flag.
 		viper.SetConfigFile(cfgFile)
 	} else {
-		// Find home directory.
-		home, err := homedir.Dir()
+		// Find home directory.  without one (e.g. in a container) there
+		// is no config file to find, but flags and the environment work.
+		home, err := homeDir()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			logger.Warn("can't find the home directory; skipping the config file", "error", err)
+			viper.AutomaticEnv()
+			return
 		}
 
 		// Search config in home directory with name ".cidr" (without extension).
@@ -235,7 +1302,18 @@ func initConfig() {
 	viper.AutomaticEnv() // read in environment variables that match
 
 	// If a config file is found, read it in.
-	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+	err := viper.ReadInConfig()
+	if err == nil {
+		if !resultOnly {
+			fmt.Println("Using config file:", viper.ConfigFileUsed())
+		}
+		return
+	}
+
+	// a missing config file is never an error, but with --strict-config
+	// one which can't be read or parsed is fatal
+	if _, notFound := err.(viper.ConfigFileNotFoundError); strictConfig && !notFound {
+		fmt.Printf("error reading config file %s: %s\n", viper.ConfigFileUsed(), err)
+		os.Exit(1)
 	}
 }
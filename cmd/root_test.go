@@ -0,0 +1,739 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// run the root command with the args, returning what it printed
+func runRoot(t *testing.T, args ...string) string {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	RootCmd.SetArgs(args)
+	cmd, runErr := RootCmd.ExecuteC()
+	w.Close()
+	resetFlags(cmd)
+
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runErr != nil {
+		t.Fatalf("cidr %s: %s", strings.Join(args, " "), runErr)
+	}
+	return string(out)
+}
+
+// cobra keeps flag values between runs, so put back the defaults of
+// any the last run changed
+func resetFlags(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			sv.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	})
+}
+
+func TestInitConfigWithoutHomeDir(t *testing.T) {
+	saved := homeDir
+	defer func() { homeDir = saved }()
+
+	called := false
+	homeDir = func() (string, error) {
+		called = true
+		return "", errors.New("no home directory")
+	}
+
+	out := runRoot(t, "--mask", "8.8.8.8", "--within", "0.0.0.0", "1.2.3.4")
+	if !called {
+		t.Fatal("initConfig did not look for the home directory")
+	}
+	if out != "1.2.3.4\n" {
+		t.Errorf("expected the command to run without a config file, got %q", out)
+	}
+
+	if err := saveProfile("test", map[string]string{"mask": "8.8.8.8"}); err == nil {
+		t.Error("expected saveProfile to fail without a home directory")
+	}
+}
+
+// run with -race: translations share nothing but their inputs, so
+// concurrent callers, even with one options value, must not race
+func TestTranslateConcurrently(t *testing.T) {
+	opts := translateOptions{withinMask: []int{16, 16}, verify: true}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 200)
+	for i := range 100 {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			got, err := Translate(fmt.Sprintf("%d.1.1", i), "16.8.8", "172.16.0.0")
+			if err != nil {
+				errs <- err
+				return
+			}
+			if want := fmt.Sprintf("172.%d.1.1", 16|i); got != want {
+				errs <- fmt.Errorf("Translate gave %s, want %s", got, want)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			got, err := translate(fmt.Sprintf("%d.2", i), "16.16", "10.0", opts)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if want := fmt.Sprintf("0.%d.0.2", 10|i); got != want {
+				errs <- fmt.Errorf("translate gave %s, want %s", got, want)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestTranslateWhileSettingsChange(t *testing.T) {
+	saved, savedLevel := logger, logLevelVar.Level()
+	defer func() {
+		logger = saved
+		logLevelVar.Set(savedLevel)
+	}()
+	logger = slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: &logLevelVar}))
+
+	done := make(chan struct{})
+	toggled := make(chan struct{})
+	go func() {
+		defer close(toggled)
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if i%2 == 0 {
+				logLevelVar.Set(slog.LevelDebug)
+			} else {
+				logLevelVar.Set(slog.LevelWarn)
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 100)
+	for i := range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// every other translation traces its bits to its own writer
+			var opts translateOptions
+			var trace strings.Builder
+			if i%2 == 0 {
+				opts.trace = &trace
+			}
+			got, err := translate(fmt.Sprintf("%d.1.1", i), "16.8.8", "172.16.0.0", opts)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if want := fmt.Sprintf("172.%d.1.1", 16|i); got != want {
+				errs <- fmt.Errorf("translate gave %s, want %s", got, want)
+			}
+			if i%2 == 0 && !strings.Contains(trace.String(), "value ") {
+				errs <- fmt.Errorf("translation %d wrote no trace", i)
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+	<-toggled
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestMaskTable(t *testing.T) {
+	for n := 0; n <= 32; n++ {
+		want := uint32(uint64(1)<<uint(n) - 1)
+		if got := generateAndMask(n); got != want {
+			t.Errorf("generateAndMask(%d) = 0x%08x, want 0x%08x", n, got, want)
+		}
+	}
+}
+
+func BenchmarkComputeCIDR(b *testing.B) {
+	fields := []int{12, 8, 6, 6}
+	values := []int{2049, 17, 33, 9}
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := computeCIDR(fields, values, false, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTranslate(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := Translate("2049.17.33.9", "12.8.6.6", "0.0.0.0"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestFieldCounts(t *testing.T) {
+	tests := []struct {
+		value string
+		opts  translateOptions
+		want  string // a substring of the error, or the result
+	}{
+		{"1.2", translateOptions{}, "mask defines 4 fields but value only provides 2"},
+		{"1.2.3.4.5", translateOptions{}, "value provides 5 fields but mask only defines 4"},
+		{"1.2", translateOptions{padShort: true}, "1.2.0.0"},
+		{"1.2.3.4.5", translateOptions{padShort: true}, "value provides 5 fields but mask only defines 4"},
+		{"1.2.3.4", translateOptions{}, "1.2.3.4"},
+	}
+
+	for _, tt := range tests {
+		got, err := translate(tt.value, "8.8.8.8", "0.0.0.0", tt.opts)
+		if err != nil {
+			got = err.Error()
+		}
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("translate(%q, padShort %v) = %q, want %q", tt.value, tt.opts.padShort, got, tt.want)
+		}
+	}
+}
+
+func TestFieldOrder(t *testing.T) {
+	msb, err := translate("1.2.3", "8.8.16", "0.0.0.0", translateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lsb, err := translate("1.2.3", "8.8.16", "0.0.0.0", translateOptions{lsbFirst: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// msb packs 1 into the top octet; lsb puts the last field, 3, on top
+	if msb != "1.2.0.3" {
+		t.Errorf("msb packing gave %s, want 1.2.0.3", msb)
+	}
+	if lsb != "0.3.2.1" {
+		t.Errorf("lsb packing gave %s, want 0.3.2.1", lsb)
+	}
+}
+
+func TestFieldOverflowMessage(t *testing.T) {
+	_, err := computeCIDR([]int{12, 8, 6, 6}, []int{1, 2, 70, 3}, false, nil)
+	if err == nil {
+		t.Fatal("expected 70 to overflow a 6 bit field")
+	}
+	if want := "field #2 (70) exceeds max 63 for width 6"; err.Error() != want {
+		t.Errorf("got %q, want %q", err, want)
+	}
+}
+
+func TestPadShortValue(t *testing.T) {
+	tests := []struct {
+		value string
+		opts  translateOptions
+		want  string
+	}{
+		{"5", translateOptions{padShort: true}, "15.0.0.0"},
+		{"5", translateOptions{padShort: true, padHigh: true}, "10.0.0.5"},
+		{"1.2", translateOptions{padShort: true}, "11.2.0.0"},
+		{"1.2", translateOptions{padShort: true, padHigh: true}, "10.0.1.2"},
+		{"1.2.3", translateOptions{padShort: true, padHigh: true}, "10.1.2.3"},
+	}
+
+	for _, tt := range tests {
+		got, err := translate(tt.value, "8.8.8.8", "10.0.0.0", tt.opts)
+		if err != nil {
+			t.Errorf("translate(%q, padHigh %v): %s", tt.value, tt.opts.padHigh, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("translate(%q, padHigh %v) = %s, want %s", tt.value, tt.opts.padHigh, got, tt.want)
+		}
+	}
+}
+
+func TestPadShortValueBase(t *testing.T) {
+	tests := []struct {
+		value string
+		base  int
+		want  string
+	}{
+		{"11", 2, "0.0.0.3"},
+		{"ff", 16, "0.0.0.255"},
+		{"17", 8, "0.0.0.15"},
+		{"1.10", 2, "0.0.1.2"},
+	}
+
+	for _, tt := range tests {
+		opts := translateOptions{padShort: true, padHigh: true, fieldBase: tt.base}
+		got, err := translate(tt.value, "8.8.8.8", "0.0.0.0", opts)
+		if err != nil {
+			t.Errorf("translate(%q, base %d): %s", tt.value, tt.base, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("translate(%q, base %d) = %s, want %s", tt.value, tt.base, got, tt.want)
+		}
+	}
+
+	if _, err := translate("12", "8.8.8.8", "0.0.0.0", translateOptions{padShort: true, fieldBase: 2}); err == nil {
+		t.Error("expected a lone field with a digit outside base 2 to be rejected")
+	}
+}
+
+func TestAllowShortValueFieldBase(t *testing.T) {
+	got := runRoot(t, "--mask", "8.8.8.8", "--allow-short-value", "--pad", "high", "--field-base", "2", "--within", "0.0.0.0", "11")
+	if want := "0.0.0.3"; strings.TrimSpace(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestMaskSumMessage(t *testing.T) {
+	tests := []struct {
+		mask string
+		want string // the error, or empty for none
+	}{
+		{"12.8.6.6", ""},
+		{"12.8.6", "mask defines 26 bits, expected 32 (6 too few)"},
+		{"12.8.6.10", "mask defines 36 bits, expected 32 (4 too many)"},
+		{"16.17", "mask defines 33 bits, expected 32 (1 too many)"},
+	}
+
+	for _, tt := range tests {
+		_, _, err := parseNamedMaskBits(tt.mask, 32, 1)
+		got := ""
+		if err != nil {
+			got = err.Error()
+		}
+		if got != tt.want {
+			t.Errorf("parseNamedMaskBits(%q) = %q, want %q", tt.mask, got, tt.want)
+		}
+	}
+}
+
+func TestIntegerInput(t *testing.T) {
+	tests := []struct {
+		value string
+		opts  translateOptions
+		want  string
+	}{
+		{"2886733893", translateOptions{integerInput: true}, "172.16.16.69"},
+		{"2886795333", translateOptions{integerInput: true}, "172.17.0.69"},
+		{"0", translateOptions{integerInput: true}, "0.0.0.0"},
+		{"4294967295", translateOptions{integerInput: true}, "255.255.255.255"},
+		{"1157632172", translateOptions{integerInput: true, littleEndian: true}, "172.16.0.69"},
+		// dotted values are still read as fields
+		{"172.16.16.69", translateOptions{integerInput: true}, "172.16.16.69"},
+	}
+
+	for _, tt := range tests {
+		got, err := translate(tt.value, "8.8.8.8", "0.0.0.0", tt.opts)
+		if err != nil {
+			t.Errorf("translate(%q): %s", tt.value, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("translate(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+
+	// without --integer-input, a lone integer is not an address
+	if got, err := translate("2886795333", "8.8.8.8", "0.0.0.0", translateOptions{}); err == nil {
+		t.Errorf("translated 2886795333 to %s without --integer-input", got)
+	}
+	if got, err := translate("4294967296", "8.8.8.8", "0.0.0.0", translateOptions{integerInput: true}); err == nil {
+		t.Errorf("translated 4294967296 to %s, which is more than 32 bits", got)
+	}
+}
+
+func TestFieldBase(t *testing.T) {
+	tests := []struct {
+		value string
+		base  int
+		want  string
+	}{
+		{"ff.0.0.0", 16, "255.0.0.0"},
+		{"ac.10.10.41", 16, "172.16.16.65"},
+		{"AC.10.10.41", 16, "172.16.16.65"},
+		{"z.10.a.0", 36, "35.36.10.0"},
+		{"4s.g.g.1t", 36, "172.16.16.65"},
+		{"172.16.16.65", 10, "172.16.16.65"},
+	}
+
+	for _, tt := range tests {
+		got, err := translate(tt.value, "8.8.8.8", "0.0.0.0", translateOptions{fieldBase: tt.base})
+		if err != nil {
+			t.Errorf("translate(%q, base %d): %s", tt.value, tt.base, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("translate(%q, base %d) = %s, want %s", tt.value, tt.base, got, tt.want)
+		}
+	}
+
+	// mask widths stay decimal whatever the value's base
+	got, err := translate("1.1", "16.16", "0.0.0.0", translateOptions{fieldBase: 16})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "0.1.0.1" {
+		t.Errorf("got %s, want 0.1.0.1", got)
+	}
+	if _, err := translate("fg.0.0.0", "8.8.8.8", "0.0.0.0", translateOptions{fieldBase: 16}); err == nil {
+		t.Error("expected 'g' to be rejected in base 16")
+	}
+}
+
+func TestSeparatorErrors(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"172.16.0.0.", "'172.16.0.0.' has an empty trailing field; remove the trailing '.'"},
+		{".172.16.0.0", "'.172.16.0.0' has an empty leading field; remove the leading '.'"},
+		{"172.16..0", "'172.16..0' has an empty field #2; remove the repeated '.'"},
+		{"172:16:0:0:", "'172:16:0:0:' has an empty trailing field; remove the trailing ':'"},
+		{"ff.0.0.0", "unexpected character 'f' at position 1 in 'ff.0.0.0'; fields are decimal numbers, or hex with a 0x prefix"},
+		{"1.ff.0.0", "unexpected character 'f' at position 3 in '1.ff.0.0'; fields are separated by '.'"},
+	}
+
+	for _, tt := range tests {
+		_, err := translate(tt.value, "8.8.8.8", "0.0.0.0", translateOptions{})
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("translate(%q) = %v, want %q", tt.value, err, tt.want)
+		}
+	}
+}
+
+func TestPastedCharacters(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"“172.16.0.1”", "unexpected character U+201C '“' at position 1"},
+		{"172.16.0.1”", "unexpected character U+201D '”' at position 11"},
+		{"‘172.16.0.1’", "unexpected character U+2018 '‘' at position 1"},
+		{"172 16 0 1", "unexpected character U+00A0 at position 4"},
+		{"172.16.0.1\t", "unexpected character U+0009 at position 11"},
+	}
+
+	for _, tt := range tests {
+		_, err := translate(tt.value, "8.8.8.8", "0.0.0.0", translateOptions{})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("translate(%q) = %v, want an error containing %q", tt.value, err, tt.want)
+		}
+	}
+}
+
+func TestPrefixedWithin(t *testing.T) {
+	tests := []struct {
+		value, mask, within string
+		want                string // the result, or a substring of the error
+	}{
+		{"0.1.1.1", "12.8.6.6", "172.16.0.0/12", "172.16.16.65"},
+		{"0.255.63.63", "12.8.6.6", "172.16.0.0/12", "172.31.255.255"},
+		{"1.1.1.1", "12.8.6.6", "172.16.0.0/12", "fixed by the within 172.16.0.0/12"},
+		{"0.1.1", "16.8.8", "10.0.0.0/16", "10.0.1.1"},
+		{"1.1.1", "16.8.8", "10.0.0.0/16", "fixed by the within 10.0.0.0/16"},
+		// /0 fixes nothing
+		{"1.1.1.1", "8.8.8.8", "0.0.0.0/0", "1.1.1.1"},
+		{"0.1.1.1", "12.8.6.6", "172.16.0.0/33", "invalid prefix length"},
+	}
+
+	for _, tt := range tests {
+		got, err := translate(tt.value, tt.mask, tt.within, translateOptions{})
+		if err != nil {
+			got = err.Error()
+		}
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("translate(%q, %q, %q) = %q, want %q", tt.value, tt.mask, tt.within, got, tt.want)
+		}
+	}
+}
+
+func TestWidthUnit(t *testing.T) {
+	tests := []struct {
+		value, mask string
+		unit        int
+		want        string // the result, or a substring of the error
+	}{
+		{"1.2.3.4", "8.8.8.8", 1, "1.2.3.4"},
+		{"1.2.3.4", "1.1.1.1", 8, "1.2.3.4"},
+		{"1.515", "2.2", 8, "0.1.2.3"},
+		{"1.2.3", "1.1.2", 8, "1.2.0.3"},
+		{"1.2.3.4", "1.1.1.1", 1, "mask defines 4 bits, expected 32 (28 too few)"},
+		{"1.2.3.4.5", "1.1.1.1.1", 8, "mask defines 40 bits, expected 32 (8 too many)"},
+	}
+
+	for _, tt := range tests {
+		got, err := translate(tt.value, tt.mask, "0.0.0.0", translateOptions{widthUnit: tt.unit})
+		if err != nil {
+			got = err.Error()
+		}
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("translate(%q, %q, unit %d) = %q, want %q", tt.value, tt.mask, tt.unit, got, tt.want)
+		}
+	}
+
+	for unit, want := range map[string]int{"bits": 1, "bytes": 8} {
+		got, err := parseWidthUnit(unit)
+		if err != nil || got != want {
+			t.Errorf("parseWidthUnit(%q) = %d, %v, want %d", unit, got, err, want)
+		}
+	}
+	if _, err := parseWidthUnit("nibbles"); err == nil {
+		t.Error("expected an unknown width unit to be rejected")
+	}
+}
+
+func TestLenientMask(t *testing.T) {
+	saved := logger
+	defer func() { logger = saved }()
+	var logged strings.Builder
+	logger = slog.New(slog.NewTextHandler(&logged, &slog.HandlerOptions{Level: slog.LevelWarn}))
+
+	tests := []struct {
+		value, mask string
+		want        string // the result, or a substring of the error
+	}{
+		// under 32 bits, packing into the low bits
+		{"1.2.3", "8.8.8", "0.1.2.3"},
+		{"1.1", "4.4", "0.0.0.17"},
+		// over 32 bits, whose leading bits must be zero
+		{"0.1.2.3.4", "8.8.8.8.8", "1.2.3.4"},
+		{"1.1.2.3.4", "8.8.8.8.8", "the leading 8 bits of the mask must be zero"},
+	}
+
+	for _, tt := range tests {
+		logged.Reset()
+		got, err := translate(tt.value, tt.mask, "0.0.0.0", translateOptions{lenient: true})
+		if err != nil {
+			got = err.Error()
+		}
+		if !strings.Contains(got, tt.want) {
+			t.Errorf("translate(%q, %q) = %q, want %q", tt.value, tt.mask, got, tt.want)
+		}
+		if !strings.Contains(logged.String(), "lenient mask does not match the address width") {
+			t.Errorf("translate(%q, %q) logged %q, expected a warning", tt.value, tt.mask, logged.String())
+		}
+	}
+
+	// without --lenient the same masks are errors
+	for _, mask := range []string{"8.8.8", "8.8.8.8.8"} {
+		if _, err := translate("0.1.2.3.4", mask, "0.0.0.0", translateOptions{}); err == nil {
+			t.Errorf("expected %s to be rejected without --lenient", mask)
+		}
+	}
+}
+
+// run in a subprocess by TestExecuteRecoversPanic, since Execute exits
+func TestExecutePanicHelper(t *testing.T) {
+	if os.Getenv("CIDR_PANIC_HELPER") != "1" {
+		t.Skip("run by TestExecuteRecoversPanic")
+	}
+	RootCmd.AddCommand(&cobra.Command{
+		Use: "panic",
+		Run: func(cmd *cobra.Command, args []string) { panic("boom") },
+	})
+	RootCmd.SetArgs(strings.Fields(os.Getenv("CIDR_PANIC_ARGS")))
+	Execute()
+}
+
+func TestExecuteRecoversPanic(t *testing.T) {
+	tests := []struct {
+		args  string
+		want  string
+		trace bool
+	}{
+		{"panic", "internal error: boom (rerun with --debug for a stack trace)\n", false},
+		{"panic --debug", "panic: boom", true},
+	}
+
+	for _, tt := range tests {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestExecutePanicHelper$")
+		cmd.Env = append(os.Environ(), "CIDR_PANIC_HELPER=1", "CIDR_PANIC_ARGS="+tt.args)
+		var stderr strings.Builder
+		cmd.Stderr = &stderr
+		err := cmd.Run()
+
+		var exit *exec.ExitError
+		if !errors.As(err, &exit) || exit.ExitCode() != 2 {
+			t.Errorf("cidr %s: %v, want exit status 2", tt.args, err)
+		}
+		if got := stderr.String(); tt.trace {
+			if !strings.HasPrefix(got, tt.want) || !strings.Contains(got, "goroutine") {
+				t.Errorf("cidr %s wrote %q, want a stack trace", tt.args, got)
+			}
+		} else if got != tt.want {
+			t.Errorf("cidr %s wrote %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestSeparatorClass(t *testing.T) {
+	tests := []struct {
+		class, value string
+		want         string // a substring of the error, or empty to accept
+	}{
+		{"any", "1.2.3.4", ""},
+		{"any", "1:2:3:4", ""},
+		{"any", "1-2-3-4", ""},
+		{"dot", "1.2.3.4", ""},
+		{"dot", "1:2:3:4", "unexpected separator ':' at position 2 in '1:2:3:4'; --separator-class dot allows only '.'"},
+		{"dot", "1.2:3.4", "unexpected separator ':' at position 4"},
+		{"dot", "0x1.0xff.3.4", ""},
+		{"colon", "1:2:3:4", ""},
+		{"colon", "1.2.3.4", "--separator-class colon allows only ':'"},
+		{"dash", "1-2-3-4", ""},
+		{"dash", "1-2 3-4", "unexpected separator ' '"},
+		{"slash", "1.2.3.4", "unknown separator class 'slash'"},
+	}
+
+	for _, tt := range tests {
+		err := checkSeparatorClass(tt.value, tt.class)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("--separator-class %s rejected %q: %s", tt.class, tt.value, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("--separator-class %s of %q = %v, want %q", tt.class, tt.value, err, tt.want)
+		}
+	}
+
+	if got := runRoot(t, "--mask", "8.8.8.8", "--separator-class", "colon", "1:2:3:4"); got != "1.2.3.4\n" {
+		t.Errorf("--separator-class colon printed %q", got)
+	}
+	if got := runRoot(t, "--mask", "8.8.8.8", "--separator-class", "dot", "1:2:3:4"); !strings.Contains(got, "unexpected separator ':'") {
+		t.Errorf("--separator-class dot accepted 1:2:3:4, printing %q", got)
+	}
+}
+
+func TestLooksSwapped(t *testing.T) {
+	tests := []struct {
+		value, mask string
+		bits        int
+		want        bool
+	}{
+		{"12.8.6.6", "0.1.1.1", 32, true},
+		{"16.16", "10.20", 32, true},
+		{"12.8.6.6", "255.255.0.0", 32, true},
+		{"0.1.1.1", "12.8.6.6", 32, false},
+		// a value of widths with a valid mask is ambiguous
+		{"8.8.8.8", "8.8.8.8", 32, false},
+		{"8.8.8.8", "16.16", 32, false},
+		{"1.2.3.4", "0.1.1.1", 32, false},
+		{"0.16.16", "0.1.1", 32, false},
+		{"64.64", "0.1", 128, true},
+		{"64.64", "0.1", 32, false},
+	}
+
+	for _, tt := range tests {
+		if got := looksSwapped(tt.value, tt.mask, tt.bits); got != tt.want {
+			t.Errorf("looksSwapped(%q, %q, %d) = %t, want %t", tt.value, tt.mask, tt.bits, got, tt.want)
+		}
+	}
+}
+
+func TestSwapHint(t *testing.T) {
+	const hint = "hint: did you swap --mask and the value? '12.8.6.6' looks like a mask and '0.1.1.1' doesn't\n"
+
+	stderr := captureStderr(t, func() { runRoot(t, "--hints", "--mask", "0.1.1.1", "12.8.6.6") })
+	if stderr != hint {
+		t.Errorf("--hints wrote %q to stderr, want %q", stderr, hint)
+	}
+	stderr = captureStderr(t, func() { runRoot(t, "--mask", "0.1.1.1", "12.8.6.6") })
+	if stderr != "" {
+		t.Errorf("without --hints %q was written to stderr", stderr)
+	}
+	stderr = captureStderr(t, func() { runRoot(t, "--hints", "--mask", "12.8.6.6", "0.1.1.1") })
+	if stderr != "" {
+		t.Errorf("--hints wrote %q for arguments in the right order", stderr)
+	}
+}
+
+func TestTraceBits(t *testing.T) {
+	const want = `value 0.1.1.1:
+  field #0 (12 bits): 0x00000000
+  field #1 (8 bits): 0x00000001
+  field #2 (6 bits): 0x00000041
+  field #3 (6 bits): 0x00001041
+within 172.16.0.0:
+  field #0 (8 bits): 0x000000ac
+  field #1 (8 bits): 0x0000ac10
+  field #2 (8 bits): 0x00ac1000
+  field #3 (8 bits): 0xac100000
+`
+
+	var out string
+	trace := captureStderr(t, func() {
+		out = runRoot(t, "--trace-bits", "--mask", "12.8.6.6", "--within", "172.16.0.0", "0.1.1.1")
+	})
+	if out != "172.16.16.65\n" {
+		t.Errorf("--trace-bits changed the result to %q", out)
+	}
+	if trace != want {
+		t.Errorf("--trace-bits wrote\n%s\nwant\n%s", trace, want)
+	}
+
+	trace = captureStderr(t, func() { runRoot(t, "--mask", "12.8.6.6", "0.1.1.1") })
+	if trace != "" {
+		t.Errorf("without --trace-bits %q was written to stderr", trace)
+	}
+
+	// a library caller chooses where the trace goes
+	var sb strings.Builder
+	got, err := translate("0.1.1.1", "12.8.6.6", "172.16.0.0", translateOptions{trace: &sb})
+	if err != nil || got != "172.16.16.65" {
+		t.Errorf("translate with a trace = %q, %v", got, err)
+	}
+	if sb.String() != want {
+		t.Errorf("the trace writer got\n%s\nwant\n%s", sb.String(), want)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// sampleCmd represents the sample command
+var sampleCmd = &cobra.Command{
+	Use:   "sample",
+	Short: "print addresses spread evenly across a network",
+	Long: `Print --count addresses spread evenly across the --within network,
+e.g. as targets for reachability probes.  The addresses are a stride of
+size/count apart, starting at the network address.  A count larger than
+the network prints every address once.  Example:
+
+	cidr sample --within 10.0.0.0/16 --count 4
+
+returns
+
+	10.0.0.0
+	10.0.64.0
+	10.0.128.0
+	10.0.192.0
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		within, err := cmd.Flags().GetString("within")
+		if err != nil {
+			panic(err)
+		}
+		count, err := cmd.Flags().GetUint64("count")
+		if err != nil {
+			panic(err)
+		}
+
+		network, err := parseBlock(within)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		if count == 0 {
+			fmt.Printf("--count must be at least 1\n")
+			os.Exit(1)
+		}
+
+		sample(network, count, func(addr uint32) {
+			fmt.Printf("%s\n", formatIPv4(addr))
+		})
+	},
+}
+
+// hand fn count addresses a stride of size/count apart, from the
+// network address.  a count larger than the network is capped at its size.
+func sample(network block, count uint64, fn func(addr uint32)) {
+	if count > network.size() {
+		logger.Warn("the count is larger than the network; sampling every address",
+			"count", count, "network", network.String(), "size", network.size())
+		count = network.size()
+	}
+
+	stride := network.size() / count
+	for i := uint64(0); i < count; i++ {
+		fn(uint32(uint64(network.base) + i*stride))
+	}
+}
+
+func init() {
+	RootCmd.AddCommand(sampleCmd)
+
+	sampleCmd.Flags().StringP("within", "w", "", "the network to sample, e.g. 10.0.0.0/16")
+	sampleCmd.Flags().Uint64("count", 10, "the number of addresses to print")
+}
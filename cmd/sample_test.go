@@ -0,0 +1,76 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestSampleSpacing(t *testing.T) {
+	tests := []struct {
+		network string
+		count   uint64
+		stride  uint32
+	}{
+		{"10.0.0.0/16", 4, 16384},
+		{"10.0.0.0/16", 10, 6553},
+		{"192.168.1.0/24", 3, 85},
+		{"192.168.1.0/24", 256, 1},
+		{"0.0.0.0/0", 8, 1 << 29},
+		{"10.0.0.5/32", 1, 1},
+	}
+
+	for _, tt := range tests {
+		network := parseBlocks(t, tt.network)[0]
+		var addrs []uint32
+		sample(network, tt.count, func(addr uint32) { addrs = append(addrs, addr) })
+
+		if uint64(len(addrs)) != tt.count {
+			t.Errorf("sample(%s, %d) gave %d addresses", tt.network, tt.count, len(addrs))
+			continue
+		}
+		if addrs[0] != network.base {
+			t.Errorf("sample(%s, %d) started at %s", tt.network, tt.count, formatIPv4(addrs[0]))
+		}
+		for i := 1; i < len(addrs); i++ {
+			if addrs[i]-addrs[i-1] != tt.stride {
+				t.Errorf("sample(%s, %d) stepped from %s to %s, want a stride of %d",
+					tt.network, tt.count, formatIPv4(addrs[i-1]), formatIPv4(addrs[i]), tt.stride)
+				break
+			}
+		}
+		if last := addrs[len(addrs)-1]; last > network.last() {
+			t.Errorf("sample(%s, %d) left the network at %s", tt.network, tt.count, formatIPv4(last))
+		}
+	}
+}
+
+func TestSampleLargeCount(t *testing.T) {
+	// more addresses than the network holds prints each one once
+	var addrs []uint32
+	sample(parseBlocks(t, "10.0.0.0/30")[0], 10, func(addr uint32) { addrs = append(addrs, addr) })
+	if len(addrs) != 4 {
+		t.Fatalf("sampling 10 of a /30 gave %d addresses", len(addrs))
+	}
+	for i, addr := range addrs {
+		if want := uint32(10<<24 + i); addr != want {
+			t.Errorf("address %d is %s, want %s", i, formatIPv4(addr), formatIPv4(want))
+		}
+	}
+}
+
+func TestSampleCommand(t *testing.T) {
+	want := "10.0.0.0\n10.0.64.0\n10.0.128.0\n10.0.192.0\n"
+	if got := runRoot(t, "sample", "--within", "10.0.0.0/16", "--count", "4"); got != want {
+		t.Errorf("sample printed %q, want %q", got, want)
+	}
+}
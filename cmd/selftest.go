@@ -0,0 +1,123 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// selftestCmd represents the selftest command
+var selftestCmd = &cobra.Command{
+	Use:    "selftest",
+	Short:  "check that random values survive a pack and unpack",
+	Hidden: true,
+	Long: `Translate random values with the mask, unpack each result, and
+report any whose fields don't come back unchanged.  The values are drawn
+from a fixed seed, so a failure can be reproduced.  Example:
+
+	cidr selftest --mask 12.8.6.6 --count 10000
+
+returns
+
+	10000 roundtrips ok
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		mask, err := cmd.Flags().GetString("mask")
+		if err != nil {
+			panic(err)
+		}
+		count, err := cmd.Flags().GetInt("count")
+		if err != nil {
+			panic(err)
+		}
+		seed, err := cmd.Flags().GetUint64("seed")
+		if err != nil {
+			panic(err)
+		}
+
+		failed, err := selftest(os.Stdout, mask, count, seed, translateOptions{})
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		if failed > 0 {
+			fmt.Printf("%d of %d roundtrips failed\n", failed, count)
+			os.Exit(1)
+		}
+		if !resultOnly {
+			fmt.Printf("%d roundtrips ok\n", count)
+		}
+	},
+}
+
+// pack count random values with the mask and unpack them again, writing
+// each mismatch to w.  returns the number of mismatches.
+func selftest(w io.Writer, mask string, count int, seed uint64, opts translateOptions) (int, error) {
+	fields, err := parseMask(mask)
+	if err != nil {
+		return 0, err
+	}
+
+	rnd := rand.New(rand.NewPCG(seed, seed))
+	failed := 0
+	for n := 0; n < count; n++ {
+		values := make([]string, len(fields))
+		want := make([]int, len(fields))
+		for i, f := range fields {
+			want[i] = int(rnd.Uint32() & generateAndMask(f))
+			values[i] = strconv.Itoa(want[i])
+		}
+		value := strings.Join(values, ".")
+
+		str, err := translate(value, mask, "0.0.0.0", opts)
+		if err != nil {
+			fmt.Fprintf(w, "%s: %s\n", value, err)
+			failed++
+			continue
+		}
+		addr, err := parseIPv4(str)
+		if err != nil {
+			fmt.Fprintf(w, "%s: %s\n", value, err)
+			failed++
+			continue
+		}
+
+		got := unpackFields(addr, fields)
+		for i := range want {
+			if got[i] != want[i] {
+				fmt.Fprintf(w, "%s: packed to %s, which unpacks to %s\n", value, str, formatMask(got))
+				failed++
+				break
+			}
+		}
+	}
+
+	return failed, nil
+}
+
+func init() {
+	RootCmd.AddCommand(selftestCmd)
+
+	selftestCmd.Flags().StringP("mask", "m", "8:13:4:7", "bitmask to exercise")
+	selftestCmd.Flags().Int("count", 1000, "the number of random values to check")
+	selftestCmd.Flags().Uint64("seed", 1, "the seed of the random values")
+}
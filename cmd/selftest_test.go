@@ -0,0 +1,81 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSelftest(t *testing.T) {
+	for _, mask := range []string{"8.8.8.8", "12.8.6.6", "8:13:4:7", "1.31", "16:net,16:host"} {
+		var sb strings.Builder
+		failed, err := selftest(&sb, mask, 500, 1, translateOptions{})
+		if err != nil {
+			t.Errorf("selftest(%q): %s", mask, err)
+			continue
+		}
+		if failed != 0 {
+			t.Errorf("selftest(%q) had %d failures:\n%s", mask, failed, sb.String())
+		}
+	}
+}
+
+func TestSelftestCatchesBrokenPacking(t *testing.T) {
+	// drop the lowest bit of every result
+	broken := func(fields, values []int, lsbFirst bool, trace io.Writer) ([]int, error) {
+		octets, err := computeCIDR(fields, values, lsbFirst, trace)
+		if err != nil {
+			return nil, err
+		}
+		octets[3] &^= 1
+		return octets, nil
+	}
+
+	var sb strings.Builder
+	failed, err := selftest(&sb, "12.8.6.6", 100, 1, translateOptions{pack: broken})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// about half of the random values have the bit set
+	if failed < 25 || failed > 75 {
+		t.Errorf("expected about half of the roundtrips to fail, %d did", failed)
+	}
+	if !strings.Contains(sb.String(), "which unpacks to") {
+		t.Errorf("expected the mismatches to be reported, got %q", sb.String())
+	}
+}
+
+func TestSelftestIsReproducible(t *testing.T) {
+	broken := func(fields, values []int, lsbFirst bool, trace io.Writer) ([]int, error) {
+		octets, err := computeCIDR(fields, values, lsbFirst, trace)
+		if err != nil {
+			return nil, err
+		}
+		octets[0] ^= 0x80
+		return octets, nil
+	}
+
+	var a, b strings.Builder
+	if _, err := selftest(&a, "8.8.8.8", 20, 42, translateOptions{pack: broken}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := selftest(&b, "8.8.8.8", 20, 42, translateOptions{pack: broken}); err != nil {
+		t.Fatal(err)
+	}
+	if a.String() != b.String() || a.Len() == 0 {
+		t.Errorf("the same seed reported different mismatches:\n%s\n%s", a.String(), b.String())
+	}
+}
@@ -0,0 +1,252 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// subnetsCmd represents the subnets command
+var subnetsCmd = &cobra.Command{
+	Use:   "subnets <cidr> <newbits>",
+	Short: "list the subnets formed by extending a prefix",
+	Long: `List the subnets formed by extending the network's prefix by newbits.
+Use --start and --limit to page through large networks, and --sort desc
+to list the highest subnets first.  --output ndjson prints each subnet
+as a JSON object on its own line.  Example:
+
+	cidr subnets --start 2 --limit 2 10.0.0.0/16 8
+
+returns
+
+	10.0.2.0/24
+	10.0.3.0/24
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 2 {
+			cmd.Usage()
+			return
+		}
+
+		pg, err := pageFlags(cmd)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		ndjson, err := enumOutputFlag(cmd)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		network, err := parseBlock(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		newbits, err := strconv.Atoi(args[1])
+		if err != nil || newbits < 0 {
+			fmt.Printf("'%s' is not a valid number of new bits\n", args[1])
+			os.Exit(1)
+		}
+
+		count, err := subnetCount(network, network.prefix+newbits)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		if err := checkSpan(cmd, pg.size(count)); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		if ndjson {
+			if err := writeSubnetsNDJSON(os.Stdout, network, network.prefix+newbits, pg); err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		err = enumerateSubnets(network, network.prefix+newbits, pg, func(i uint64, sub block) bool {
+			fmt.Printf("%s\n", sub)
+			return true
+		})
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// hostsCmd represents the hosts command
+var hostsCmd = &cobra.Command{
+	Use:   "hosts <cidr>",
+	Short: "list the addresses in a network",
+	Long: `List every address in the network, in order.  Use --start and
+--limit to page through large networks, and --sort desc to list the
+highest addresses first.  --output ndjson prints each address as a
+JSON object on its own line.  Example:
+
+	cidr hosts --start 1 --limit 3 10.0.0.0/24
+
+returns
+
+	10.0.0.1
+	10.0.0.2
+	10.0.0.3
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		pg, err := pageFlags(cmd)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		ndjson, err := enumOutputFlag(cmd)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		network, err := parseBlock(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		if err := checkSpan(cmd, pg.size(network.size())); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		if ndjson {
+			if err := writeHostsNDJSON(os.Stdout, network, pg); err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		enumerateHosts(network, pg, func(i uint64, addr uint32) bool {
+			fmt.Printf("%s\n", formatIPv4(addr))
+			return true
+		})
+	},
+}
+
+// the --start, --limit and --sort flags of an enumeration command
+func pageFlags(cmd *cobra.Command) (page, error) {
+	start, err := cmd.Flags().GetUint64("start")
+	if err != nil {
+		panic(err)
+	}
+	limit, err := cmd.Flags().GetUint64("limit")
+	if err != nil {
+		panic(err)
+	}
+	order, err := cmd.Flags().GetString("sort")
+	if err != nil {
+		panic(err)
+	}
+
+	if order != "asc" && order != "desc" {
+		return page{}, fmt.Errorf("unknown sort order '%s', expected asc or desc", order)
+	}
+	return page{start: start, limit: limit, desc: order == "desc"}, nil
+}
+
+// add the --start, --limit and --sort flags to an enumeration command
+func addPageFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64("start", 0, "skip this many entries before printing")
+	cmd.Flags().Uint64("limit", 0, "print at most this many entries (0 for all)")
+	cmd.Flags().String("sort", "asc", "print in ascending (asc) or descending (desc) order")
+}
+
+// the --output flag of an enumeration command, true for ndjson
+func enumOutputFlag(cmd *cobra.Command) (bool, error) {
+	output, err := cmd.Flags().GetString("output")
+	if err != nil {
+		panic(err)
+	}
+
+	switch output {
+	case "text":
+		return false, nil
+	case "ndjson":
+		return true, nil
+	}
+	return false, fmt.Errorf("unknown output format '%s', expected text or ndjson", output)
+}
+
+// add the --output flag to an enumeration command
+func addEnumOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("output", "o", "text", "output format: text, or ndjson for one JSON object per line as it is enumerated")
+}
+
+// refuse to print more entries than a --max-prefix network holds,
+// unless --force is given
+func checkSpan(cmd *cobra.Command, entries uint64) error {
+	maxPrefix, err := cmd.Flags().GetInt("max-prefix")
+	if err != nil {
+		panic(err)
+	}
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		panic(err)
+	}
+	if maxPrefix < 0 || maxPrefix > 32 {
+		return fmt.Errorf("invalid --max-prefix %d, expected 0-32", maxPrefix)
+	}
+
+	threshold := uint64(1) << uint(32-maxPrefix)
+	if entries > threshold && !force {
+		// only suggest the flags this command actually has
+		hint := "use --force to print them all"
+		if cmd.Flags().Lookup("limit") != nil {
+			hint = "use --limit, or --force to print them all"
+		}
+		return fmt.Errorf("refusing to print %d entries, more than the %d of a /%d; %s",
+			entries, threshold, maxPrefix, hint)
+	}
+	return nil
+}
+
+// add the --max-prefix and --force flags guarding an enumeration command
+func addSpanFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("max-prefix", 20, "refuse to print more entries than a network of this prefix length holds")
+	cmd.Flags().Bool("force", false, "print every entry, however many there are")
+}
+
+func init() {
+	RootCmd.AddCommand(subnetsCmd)
+	RootCmd.AddCommand(hostsCmd)
+
+	addPageFlags(subnetsCmd)
+	addPageFlags(hostsCmd)
+	addSpanFlags(subnetsCmd)
+	addSpanFlags(hostsCmd)
+	addEnumOutputFlag(subnetsCmd)
+	addEnumOutputFlag(hostsCmd)
+}
@@ -0,0 +1,90 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// a command with the span flags set to the args
+func spanCommand(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{Use: "test"}
+	addSpanFlags(cmd)
+	if err := cmd.Flags().Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return cmd
+}
+
+func TestCheckSpan(t *testing.T) {
+	tests := []struct {
+		args    []string
+		entries uint64
+		refused bool
+	}{
+		{nil, 4096, false},
+		{nil, 4097, true},
+		{[]string{"--force"}, 1 << 32, false},
+		{[]string{"--max-prefix", "24"}, 256, false},
+		{[]string{"--max-prefix", "24"}, 257, true},
+		{[]string{"--max-prefix", "0"}, 1 << 32, false},
+		{[]string{"--max-prefix", "32", "--force"}, 2, false},
+	}
+
+	for _, tt := range tests {
+		err := checkSpan(spanCommand(t, tt.args...), tt.entries)
+		if refused := err != nil; refused != tt.refused {
+			t.Errorf("checkSpan(%v, %d) = %v, want refused %v", tt.args, tt.entries, err, tt.refused)
+		}
+		if err != nil && !strings.Contains(err.Error(), "--force") {
+			t.Errorf("expected the refusal to mention --force, got %s", err)
+		}
+	}
+
+	if err := checkSpan(spanCommand(t, "--max-prefix", "33"), 1); err == nil {
+		t.Error("expected --max-prefix 33 to be rejected")
+	}
+}
+
+func TestSubnetsGuard(t *testing.T) {
+	// a /16 into /28s is 4096 subnets, within the default guard
+	out := runRoot(t, "subnets", "--limit", "1", "10.0.0.0/16", "12")
+	if out != "10.0.0.0/28\n" {
+		t.Errorf("got %q, want 10.0.0.0/28", out)
+	}
+	// the guard counts only the entries --limit lets through
+	out = runRoot(t, "hosts", "--limit", "2", "10.0.0.0/8")
+	if out != "10.0.0.0\n10.0.0.1\n" {
+		t.Errorf("got %q, want the first 2 hosts", out)
+	}
+}
+
+func TestCheckSpanHint(t *testing.T) {
+	// to-range-list has no --limit, so the refusal mustn't suggest it
+	err := checkSpan(spanCommand(t), 4097)
+	if err == nil || !strings.HasSuffix(err.Error(), "; use --force to print them all") {
+		t.Errorf("without --limit the refusal is %v", err)
+	}
+
+	cmd := spanCommand(t)
+	cmd.Flags().Uint64("limit", 0, "")
+	err = checkSpan(cmd, 4097)
+	if err == nil || !strings.HasSuffix(err.Error(), "; use --limit, or --force to print them all") {
+		t.Errorf("with --limit the refusal is %v", err)
+	}
+}
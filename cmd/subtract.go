@@ -0,0 +1,91 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// subtractCmd represents the subtract command
+var subtractCmd = &cobra.Command{
+	Use:   "subtract <cidr> <cidr>",
+	Short: "remove one CIDR from another, printing what remains",
+	Long: `Print the fewest CIDRs covering the first block with the second
+removed.  The second block must lie within the first.  Example:
+
+	cidr subtract 10.0.0.0/24 10.0.0.0/25
+
+returns
+
+	10.0.0.128/25
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 2 {
+			cmd.Usage()
+			return
+		}
+
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+
+		minuend, err := parseBlock(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		subtrahend, err := parseBlock(args[1])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		remaining, err := subtract(minuend, subtrahend)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		if err := writeBlocks(os.Stdout, remaining, output); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// the blocks covering b with o removed
+func subtract(b, o block) ([]block, error) {
+	if !b.contains(o) {
+		return nil, fmt.Errorf("%s is not within %s", o, b)
+	}
+
+	var result []block
+	if o.base > b.base {
+		result = append(result, ipRange{first: b.base, last: o.base - 1}.blocks()...)
+	}
+	if o.last() < b.last() {
+		result = append(result, ipRange{first: o.last() + 1, last: b.last()}.blocks()...)
+	}
+	return result, nil
+}
+
+func init() {
+	RootCmd.AddCommand(subtractCmd)
+
+	subtractCmd.Flags().StringP("output", "o", "text", "output format: text, json or yaml")
+}
@@ -0,0 +1,54 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import "testing"
+
+func TestSubtract(t *testing.T) {
+	tests := []struct {
+		from, remove string
+		want         string
+	}{
+		{"10.0.0.0/24", "10.0.0.0/25", "10.0.0.128/25"},
+		{"10.0.0.0/24", "10.0.0.128/25", "10.0.0.0/25"},
+		{"10.0.0.0/24", "10.0.0.64/26", "10.0.0.0/26 10.0.0.128/25"},
+		{"10.0.0.0/24", "10.0.0.5/32", "10.0.0.0/30 10.0.0.4/32 10.0.0.6/31 10.0.0.8/29 10.0.0.16/28 10.0.0.32/27 10.0.0.64/26 10.0.0.128/25"},
+		{"10.0.0.0/24", "10.0.0.0/24", ""},
+		{"0.0.0.0/0", "128.0.0.0/1", "0.0.0.0/1"},
+	}
+
+	for _, tt := range tests {
+		b := parseBlocks(t, tt.from, tt.remove)
+		got, err := subtract(b[0], b[1])
+		if err != nil {
+			t.Errorf("subtract(%s, %s): %s", tt.from, tt.remove, err)
+			continue
+		}
+		if blockStrings(got) != tt.want {
+			t.Errorf("subtract(%s, %s) = %s, want %s", tt.from, tt.remove, blockStrings(got), tt.want)
+		}
+	}
+}
+
+func TestSubtractOutside(t *testing.T) {
+	for _, tt := range []struct{ from, remove string }{
+		{"10.0.0.0/24", "10.0.1.0/25"},
+		{"10.0.0.0/24", "10.0.0.0/23"},
+	} {
+		b := parseBlocks(t, tt.from, tt.remove)
+		if got, err := subtract(b[0], b[1]); err == nil {
+			t.Errorf("subtract(%s, %s) = %s, expected an error", tt.from, tt.remove, blockStrings(got))
+		}
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// summarizeCmd represents the summarize command
+var summarizeCmd = &cobra.Command{
+	Use:   "summarize",
+	Short: "collapse a list of addresses into the minimal set of CIDRs",
+	Long: `Read addresses, CIDRs, or ranges (one per line) from stdin and
+print the minimal, sorted set of CIDR blocks which exactly covers them.
+Example:
+
+	printf '10.0.0.0\n10.0.0.1\n10.0.0.2-10.0.0.3\n' | cidr summarize
+
+returns
+
+	10.0.0.0/30
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		maxmem, err := cmd.Flags().GetInt("maxmem")
+		if err != nil {
+			panic(err)
+		}
+		sorted, err := cmd.Flags().GetBool("sorted")
+		if err != nil {
+			panic(err)
+		}
+		words, err := splitFlag(cmd)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		if sorted {
+			w := bufio.NewWriter(os.Stdout)
+			err = streamSummarize(os.Stdin, w, words)
+			if ferr := w.Flush(); err == nil {
+				err = ferr
+			}
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
+		ranges, err := readRanges(os.Stdin, maxmem, words)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		for _, b := range summarize(ranges) {
+			fmt.Printf("%s\n", b)
+		}
+	},
+}
+
+// the approximate number of bytes each buffered range costs, allowing
+// for slice growth and the sorted copy made by mergeRanges
+const rangeCost = 3 * 8
+
+// the --split flag of a command reading a list, true for whitespace
+func splitFlag(cmd *cobra.Command) (bool, error) {
+	split, err := cmd.Flags().GetString("split")
+	if err != nil {
+		panic(err)
+	}
+
+	switch split {
+	case "lines":
+		return false, nil
+	case "whitespace":
+		return true, nil
+	}
+	return false, fmt.Errorf("unknown split '%s', expected lines or whitespace", split)
+}
+
+// add the --split flag to a command reading a list
+func addSplitFlag(cmd *cobra.Command) {
+	cmd.Flags().String("split", "lines", "read one entry per line (lines), or split on any run of whitespace (whitespace)")
+}
+
+// hand each non-blank line to fn, trimmed, as it is read.  with words,
+// each whitespace separated word of the line is handed over instead,
+// so a pasted block of spaces, tabs and newlines reads as a list.
+func scanLines(r io.Reader, words bool, fn func(line int, text string) error) error {
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if len(text) == 0 {
+			continue
+		}
+
+		entries := []string{text}
+		if words {
+			entries = strings.Fields(text)
+		}
+		for _, e := range entries {
+			if err := fn(line, e); err != nil {
+				return err
+			}
+		}
+	}
+
+	return scanner.Err()
+}
+
+// scan one address, CIDR or range per line, skipping blank lines,
+// and hand each to fn as it is read
+func scanRanges(r io.Reader, words bool, fn func(line int, ipr ipRange) error) error {
+	return scanLines(r, words, func(line int, text string) error {
+		ipr, err := parseRange(text)
+		if err != nil {
+			return fmt.Errorf("line %d: %s", line, err)
+		}
+		return fn(line, ipr)
+	})
+}
+
+// read one CIDR per line (or word), skipping blank lines
+func readBlocks(r io.Reader, words bool) ([]block, error) {
+	var blocks []block
+	err := scanLines(r, words, func(line int, text string) error {
+		b, err := parseBlock(text)
+		if err != nil {
+			return fmt.Errorf("line %d: %s", line, err)
+		}
+		blocks = append(blocks, b)
+		return nil
+	})
+	return blocks, err
+}
+
+// read all of the ranges into memory.  summarizing requires the whole
+// input, so refuse to buffer more than maxmem MiB (0 disables the guard).
+func readRanges(r io.Reader, maxmem int, words bool) ([]ipRange, error) {
+	var ranges []ipRange
+
+	limit := maxmem * (1 << 20) / rangeCost
+	err := scanRanges(r, words, func(line int, ipr ipRange) error {
+		if maxmem > 0 && len(ranges) >= limit {
+			return fmt.Errorf("line %d: input exceeds --maxmem of %d MiB; sort the input and use --sorted to stream it",
+				line, maxmem)
+		}
+		ranges = append(ranges, ipr)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ranges, nil
+}
+
+// summarize input which is already sorted by starting address, holding
+// only the range currently being merged in memory
+func streamSummarize(r io.Reader, w io.Writer, words bool) error {
+	var current ipRange
+	started := false
+
+	err := scanRanges(r, words, func(line int, ipr ipRange) error {
+		switch {
+		case !started:
+			current, started = ipr, true
+
+		case ipr.first < current.first:
+			return fmt.Errorf("line %d: input is not sorted", line)
+
+		case uint64(ipr.first) <= uint64(current.last)+1:
+			if ipr.last > current.last {
+				current.last = ipr.last
+			}
+
+		default:
+			for _, b := range current.blocks() {
+				fmt.Fprintf(w, "%s\n", b)
+			}
+			current = ipr
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if started {
+		for _, b := range current.blocks() {
+			fmt.Fprintf(w, "%s\n", b)
+		}
+	}
+	return nil
+}
+
+func init() {
+	RootCmd.AddCommand(summarizeCmd)
+
+	summarizeCmd.Flags().Int("maxmem", 256, "refuse to buffer more than this many MiB of input (0 for no limit)")
+	summarizeCmd.Flags().Bool("sorted", false, "input is sorted by address; stream it instead of buffering")
+	addSplitFlag(summarizeCmd)
+}
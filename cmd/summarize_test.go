@@ -0,0 +1,143 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// a reader generating lines of disjoint ranges, 10.0.0.0-10.0.0.0,
+// 10.0.0.2-10.0.0.2 and so on, without holding them in memory.  it
+// samples the heap as it goes, to find the peak while it is read.
+type rangeStream struct {
+	lines, next int
+	pending     []byte
+	reads       int
+	peak        uint64
+}
+
+func (s *rangeStream) Read(p []byte) (int, error) {
+	s.reads++
+	if s.reads%16 == 0 {
+		var m runtime.MemStats
+		runtime.ReadMemStats(&m)
+		s.peak = max(s.peak, m.HeapAlloc)
+	}
+
+	for len(s.pending) < len(p) && s.next < s.lines {
+		a := formatIPv4(0x0a000000 + uint32(2*s.next))
+		s.pending = fmt.Appendf(s.pending, "%s-%s\n", a, a)
+		s.next++
+	}
+	if len(s.pending) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, s.pending)
+	s.pending = s.pending[:copy(s.pending, s.pending[n:])]
+	return n, nil
+}
+
+func TestStreamSummarizeMemoryIsBounded(t *testing.T) {
+	if testing.Short() {
+		t.Skip("streams half a million lines")
+	}
+
+	runtime.GC()
+	var before runtime.MemStats
+	runtime.ReadMemStats(&before)
+	goroutines := runtime.NumGoroutine()
+
+	// about 14 MiB of input, which buffering would hold on to
+	in := &rangeStream{lines: 500000}
+	if err := streamSummarize(in, io.Discard, false); err != nil {
+		t.Fatal(err)
+	}
+	if in.next != in.lines {
+		t.Fatalf("read %d of %d lines", in.next, in.lines)
+	}
+
+	const bound = 8 << 20
+	if growth := int64(in.peak) - int64(before.HeapAlloc); growth > bound {
+		t.Errorf("the heap grew by %d bytes while streaming, more than %d", growth, bound)
+	}
+	if n := runtime.NumGoroutine(); n > goroutines {
+		t.Errorf("streaming left %d goroutines running, %d before", n, goroutines)
+	}
+}
+
+func TestReadRangesMaxmem(t *testing.T) {
+	limit := (1 << 20) / rangeCost
+	in := &rangeStream{lines: limit + 1}
+
+	_, err := readRanges(in, 1, false)
+	if err == nil {
+		t.Fatalf("expected --maxmem 1 to refuse %d ranges", in.lines)
+	}
+	if !strings.Contains(err.Error(), "--sorted") {
+		t.Errorf("expected the error to suggest --sorted, got %s", err)
+	}
+
+	in = &rangeStream{lines: limit}
+	ranges, err := readRanges(in, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ranges) != limit {
+		t.Errorf("read %d ranges, want %d", len(ranges), limit)
+	}
+}
+
+func TestScanLinesSplit(t *testing.T) {
+	in := "10.0.0.0 10.0.0.1\t10.0.0.2\n\n  10.0.0.3 \t\n10.0.0.4-10.0.0.7\r\n"
+
+	var words []string
+	err := scanLines(strings.NewReader(in), true, func(line int, text string) error {
+		words = append(words, text)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"10.0.0.0", "10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4-10.0.0.7"}
+	if !slices.Equal(words, want) {
+		t.Errorf("split on whitespace into %q, want %q", words, want)
+	}
+
+	var lines []string
+	err = scanLines(strings.NewReader(in), false, func(line int, text string) error {
+		lines = append(lines, text)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"10.0.0.0 10.0.0.1\t10.0.0.2", "10.0.0.3", "10.0.0.4-10.0.0.7"}
+	if !slices.Equal(lines, want) {
+		t.Errorf("split on lines into %q, want %q", lines, want)
+	}
+
+	// the pasted block summarizes as if given one per line
+	ranges, err := readRanges(strings.NewReader(in), 0, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := summarize(ranges); len(got) != 1 || got[0].String() != "10.0.0.0/29" {
+		t.Errorf("summarized %v, want 10.0.0.0/29", got)
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// toRangeListCmd represents the to-range-list command
+var toRangeListCmd = &cobra.Command{
+	Use:   "to-range-list",
+	Short: "split a network into ranges of addresses, e.g. for DHCP pools",
+	Long: `Split the --within network into --count ranges of (nearly) equal
+size, or into ranges of --size addresses, and print the first and last
+address of each, separated by a tab.  When the count doesn't divide the
+network evenly the first ranges are one address larger; when the size
+doesn't, the last range is short.  Example:
+
+	cidr to-range-list --within 10.0.0.0/24 --count 4
+
+returns
+
+	10.0.0.0	10.0.0.63
+	10.0.0.64	10.0.0.127
+	10.0.0.128	10.0.0.191
+	10.0.0.192	10.0.0.255
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		within, err := cmd.Flags().GetString("within")
+		if err != nil {
+			panic(err)
+		}
+		count, err := cmd.Flags().GetUint64("count")
+		if err != nil {
+			panic(err)
+		}
+		size, err := cmd.Flags().GetUint64("size")
+		if err != nil {
+			panic(err)
+		}
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+
+		network, err := parseBlock(within)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		if countChanged(cmd, "count", "size") != 1 {
+			fmt.Printf("give exactly one of --count and --size\n")
+			os.Exit(1)
+		}
+		if cmd.Flags().Changed("size") {
+			if size == 0 {
+				fmt.Printf("--size must be at least 1\n")
+				os.Exit(1)
+			}
+			count = (network.size() + size - 1) / size
+		}
+		if count == 0 || count > network.size() {
+			fmt.Printf("--count must be 1-%d for %s\n", network.size(), network)
+			os.Exit(1)
+		}
+		if err := checkSpan(cmd, count); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		var ranges []ipRange
+		if cmd.Flags().Changed("size") {
+			ranges = rangesOfSize(network, size)
+		} else {
+			ranges = rangesOfCount(network, count)
+		}
+
+		if err := writeRangeList(os.Stdout, ranges, output); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// split the network into count ranges, the first size%count of them
+// one address larger than the rest
+func rangesOfCount(network block, count uint64) []ipRange {
+	size, extra := network.size()/count, network.size()%count
+
+	ranges := make([]ipRange, 0, count)
+	start := uint64(network.base)
+	for i := uint64(0); i < count; i++ {
+		n := size
+		if i < extra {
+			n++
+		}
+		ranges = append(ranges, ipRange{first: uint32(start), last: uint32(start + n - 1)})
+		start += n
+	}
+	return ranges
+}
+
+// split the network into ranges of size addresses; the last may be short
+func rangesOfSize(network block, size uint64) []ipRange {
+	var ranges []ipRange
+	end := uint64(network.last())
+	for start := uint64(network.base); start <= end; start += size {
+		last := min(start+size-1, end)
+		ranges = append(ranges, ipRange{first: uint32(start), last: uint32(last)})
+	}
+	return ranges
+}
+
+// a range of a range list, as written by --output json or yaml
+type rangeEntry struct {
+	Start string `json:"start" yaml:"start"`
+	End   string `json:"end" yaml:"end"`
+	Size  uint64 `json:"size" yaml:"size"`
+}
+
+// write the first and last address of each range, tab separated
+func writeRangeList(w io.Writer, ranges []ipRange, output string) error {
+	if output == "text" {
+		for _, r := range ranges {
+			fmt.Fprintf(w, "%s\t%s\n", formatIPv4(r.first), formatIPv4(r.last))
+		}
+		return nil
+	}
+
+	list := make([]rangeEntry, len(ranges))
+	for i, r := range ranges {
+		list[i] = rangeEntry{Start: formatIPv4(r.first), End: formatIPv4(r.last), Size: r.size()}
+	}
+	return writeEncoded(w, list, output)
+}
+
+func init() {
+	RootCmd.AddCommand(toRangeListCmd)
+
+	toRangeListCmd.Flags().StringP("within", "w", "", "the network to split, e.g. 10.0.0.0/24")
+	toRangeListCmd.Flags().Uint64("count", 0, "split the network into this many ranges")
+	toRangeListCmd.Flags().Uint64("size", 0, "split the network into ranges of this many addresses")
+	toRangeListCmd.Flags().StringP("output", "o", "text", "output format: text, json or yaml")
+	addSpanFlags(toRangeListCmd)
+}
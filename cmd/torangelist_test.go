@@ -0,0 +1,92 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// the ranges as "first-last" strings
+func rangeStrings(ranges []ipRange) string {
+	s := make([]string, len(ranges))
+	for i, r := range ranges {
+		s[i] = formatIPv4(r.first) + "-" + formatIPv4(r.last)
+	}
+	return strings.Join(s, " ")
+}
+
+func TestRangesOfCount(t *testing.T) {
+	tests := []struct {
+		network string
+		count   uint64
+		want    string
+	}{
+		{"10.0.0.0/24", 4, "10.0.0.0-10.0.0.63 10.0.0.64-10.0.0.127 10.0.0.128-10.0.0.191 10.0.0.192-10.0.0.255"},
+		{"10.0.0.0/24", 1, "10.0.0.0-10.0.0.255"},
+		// 256 = 86 + 85 + 85
+		{"10.0.0.0/24", 3, "10.0.0.0-10.0.0.85 10.0.0.86-10.0.0.170 10.0.0.171-10.0.0.255"},
+		{"10.0.0.0/29", 8, "10.0.0.0-10.0.0.0 10.0.0.1-10.0.0.1 10.0.0.2-10.0.0.2 10.0.0.3-10.0.0.3 " +
+			"10.0.0.4-10.0.0.4 10.0.0.5-10.0.0.5 10.0.0.6-10.0.0.6 10.0.0.7-10.0.0.7"},
+		{"0.0.0.0/0", 2, "0.0.0.0-127.255.255.255 128.0.0.0-255.255.255.255"},
+	}
+
+	for _, tt := range tests {
+		if got := rangeStrings(rangesOfCount(parseBlocks(t, tt.network)[0], tt.count)); got != tt.want {
+			t.Errorf("rangesOfCount(%s, %d) = %s, want %s", tt.network, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestRangesOfSize(t *testing.T) {
+	tests := []struct {
+		network string
+		size    uint64
+		want    string
+	}{
+		{"10.0.0.0/24", 64, "10.0.0.0-10.0.0.63 10.0.0.64-10.0.0.127 10.0.0.128-10.0.0.191 10.0.0.192-10.0.0.255"},
+		{"10.0.0.0/24", 100, "10.0.0.0-10.0.0.99 10.0.0.100-10.0.0.199 10.0.0.200-10.0.0.255"},
+		{"10.0.0.0/24", 1000, "10.0.0.0-10.0.0.255"},
+		{"255.255.255.0/24", 200, "255.255.255.0-255.255.255.199 255.255.255.200-255.255.255.255"},
+	}
+
+	for _, tt := range tests {
+		if got := rangeStrings(rangesOfSize(parseBlocks(t, tt.network)[0], tt.size)); got != tt.want {
+			t.Errorf("rangesOfSize(%s, %d) = %s, want %s", tt.network, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestToRangeListCommand(t *testing.T) {
+	want := "10.0.0.0\t10.0.0.63\n10.0.0.64\t10.0.0.127\n10.0.0.128\t10.0.0.191\n10.0.0.192\t10.0.0.255\n"
+	if got := runRoot(t, "to-range-list", "--within", "10.0.0.0/24", "--count", "4"); got != want {
+		t.Errorf("to-range-list --count 4 printed %q, want %q", got, want)
+	}
+
+	got := runRoot(t, "to-range-list", "--within", "10.0.0.0/24", "--size", "100", "--output", "json")
+	var list []rangeEntry
+	if err := json.Unmarshal([]byte(got), &list); err != nil {
+		t.Fatalf("to-range-list --output json printed %q: %s", got, err)
+	}
+	wantList := []rangeEntry{{"10.0.0.0", "10.0.0.99", 100}, {"10.0.0.100", "10.0.0.199", 100}, {"10.0.0.200", "10.0.0.255", 56}}
+	if len(list) != len(wantList) {
+		t.Fatalf("to-range-list --output json = %v, want %v", list, wantList)
+	}
+	for i := range wantList {
+		if list[i] != wantList[i] {
+			t.Errorf("range %d = %v, want %v", i, list[i], wantList[i])
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// treeCmd represents the tree command
+var treeCmd = &cobra.Command{
+	Use:   "tree <supernet> [<cidr>...]",
+	Short: "show how allocations nest within a supernet",
+	Long: `Render the supernet and its allocations as an indented tree, with
+each block shown beneath the smallest block containing it.  If no
+allocations are given on the command line they are read from stdin.
+Example:
+
+	cidr tree 10.0.0.0/16 10.0.1.0/24 10.0.1.16/28 10.0.2.0/24
+
+returns
+
+	10.0.0.0/16
+	|-- 10.0.1.0/24
+	|   ` + "`" + `-- 10.0.1.16/28
+	` + "`" + `-- 10.0.2.0/24
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) < 1 {
+			cmd.Usage()
+			return
+		}
+
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			panic(err)
+		}
+
+		root, err := parseBlock(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		var children []block
+		if len(args) > 1 {
+			for _, arg := range args[1:] {
+				b, err := parseBlock(arg)
+				if err != nil {
+					fmt.Printf("%s\n", err)
+					os.Exit(1)
+				}
+				children = append(children, b)
+			}
+		} else {
+			words, err := splitFlag(cmd)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+			children, err = readBlocks(os.Stdin, words)
+			if err != nil {
+				fmt.Printf("%s\n", err)
+				os.Exit(1)
+			}
+		}
+
+		tree, err := buildTree(root, children)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		if output == "text" {
+			fmt.Printf("%s\n", tree.CIDR)
+			writeTree(os.Stdout, tree.Children, "")
+			return
+		}
+		if err := writeEncoded(os.Stdout, tree, output); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// a node in the allocation tree
+type treeNode struct {
+	CIDR     string      `json:"cidr" yaml:"cidr"`
+	Children []*treeNode `json:"children,omitempty" yaml:"children,omitempty"`
+
+	block block
+}
+
+// nest the children beneath the root.  sorting by address and then by
+// size means each block follows the blocks which contain it.
+func buildTree(root block, children []block) (*treeNode, error) {
+	sorted := make([]block, len(children))
+	copy(sorted, children)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].base != sorted[j].base {
+			return sorted[i].base < sorted[j].base
+		}
+		return sorted[i].prefix < sorted[j].prefix
+	})
+
+	top := &treeNode{CIDR: root.String(), block: root}
+	stack := []*treeNode{top}
+	for _, b := range sorted {
+		if !root.contains(b) {
+			return nil, fmt.Errorf("%s is not within %s", b, root)
+		}
+
+		for !stack[len(stack)-1].block.contains(b) {
+			stack = stack[:len(stack)-1]
+		}
+
+		parent := stack[len(stack)-1]
+		if parent.block == b {
+			// a duplicate
+			continue
+		}
+
+		node := &treeNode{CIDR: b.String(), block: b}
+		parent.Children = append(parent.Children, node)
+		stack = append(stack, node)
+	}
+
+	return top, nil
+}
+
+// draw the nodes as ascii tree art beneath their parent
+func writeTree(w io.Writer, nodes []*treeNode, indent string) {
+	for i, n := range nodes {
+		branch, next := "|-- ", "|   "
+		if i == len(nodes)-1 {
+			branch, next = "`-- ", "    "
+		}
+		fmt.Fprintf(w, "%s%s%s\n", indent, branch, n.CIDR)
+		writeTree(w, n.Children, indent+next)
+	}
+}
+
+func init() {
+	RootCmd.AddCommand(treeCmd)
+
+	treeCmd.Flags().StringP("output", "o", "text", "output format: text, json or yaml")
+	addSplitFlag(treeCmd)
+}
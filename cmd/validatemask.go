@@ -0,0 +1,76 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// validateMaskCmd represents the validate-mask command
+var validateMaskCmd = &cobra.Command{
+	Use:   "validate-mask <mask>",
+	Short: "check that a mask is usable without supplying a value",
+	Long: `Check that a mask's fields sum to 32 bits and that every field has
+a usable width.  A valid mask prints its implied prefix, the width of the
+leading field (which the within normally fills).  An invalid mask exits
+non-zero.  Example:
+
+	cidr validate-mask 12.8.6.6
+
+returns
+
+	valid, prefix /12
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		if len(args) != 1 {
+			cmd.Usage()
+			return
+		}
+
+		prefix, err := validateMask(args[0])
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		if resultOnly {
+			fmt.Printf("%d\n", prefix)
+			return
+		}
+		fmt.Printf("valid, prefix /%d\n", prefix)
+	},
+}
+
+// validate the mask and return its implied prefix
+func validateMask(mask string) (int, error) {
+	fields, err := parseMask(mask)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, f := range fields {
+		if f < 1 {
+			return 0, fmt.Errorf("field #%d has a width of %d, expected at least 1", i, f)
+		}
+	}
+
+	return fields[0], nil
+}
+
+func init() {
+	RootCmd.AddCommand(validateMaskCmd)
+}
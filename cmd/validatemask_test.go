@@ -0,0 +1,67 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateMask(t *testing.T) {
+	tests := []struct {
+		mask string
+		want int
+	}{
+		{"12.8.6.6", 12},
+		{"8.8.8.8", 8},
+		{"31.1", 31},
+		{"12:region,8:pod,6:rack,6:host", 12},
+		{"3*8+6+2", 24},
+	}
+
+	for _, tt := range tests {
+		got, err := validateMask(tt.mask)
+		if err != nil {
+			t.Errorf("validateMask(%q): %s", tt.mask, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("validateMask(%q) = /%d, want /%d", tt.mask, got, tt.want)
+		}
+	}
+}
+
+func TestValidateMaskErrors(t *testing.T) {
+	tests := []struct {
+		mask string
+		want string
+	}{
+		{"8.8.8", "8 too few"},
+		{"8.8.8.9", "1 too many"},
+		{"0.16.16", "field #0 has a width of 0"},
+		{"32.0", "field #1 has a width of 0"},
+		{"x.y", "unexpected character 'x'"},
+	}
+
+	for _, tt := range tests {
+		_, err := validateMask(tt.mask)
+		if err == nil {
+			t.Errorf("validateMask(%q) accepted an invalid mask", tt.mask)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("validateMask(%q) = %q, want it to mention %q", tt.mask, err, tt.want)
+		}
+	}
+}
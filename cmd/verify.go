@@ -0,0 +1,97 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+)
+
+// recompute the packed address from the fields, their values and the
+// within with big integer arithmetic, independently of computeCIDR,
+// and check it matches the octets pack produced
+func verifyPacking(octets, fields, values []int, within string, opts translateOptions) error {
+	if len(octets) != 4 {
+		return fmt.Errorf("verify: expected 4 octets, found %d", len(octets))
+	}
+
+	var want big.Int
+	// OR each value in at its bit offset, starting with the lowest field
+	place := func(fields, values []int, lsbFirst bool) {
+		shift := 0
+		for n := len(fields) - 1; n >= 0; n-- {
+			i := n
+			if lsbFirst {
+				i = len(fields) - n - 1
+			}
+			v := new(big.Int).Lsh(big.NewInt(int64(values[i])), uint(shift))
+			want.Or(&want, v)
+			shift += fields[i]
+		}
+	}
+	place(fields, values, opts.lsbFirst)
+
+	withinFields := opts.withinMask
+	if withinFields == nil {
+		withinFields = []int{8, 8, 8, 8}
+	}
+	withinValues, err := parse(within)
+	if err != nil {
+		return fmt.Errorf("verify: %s", err)
+	}
+	if len(withinValues) != len(withinFields) {
+		return fmt.Errorf("verify: the within %s has %d fields, expected %d",
+			within, len(withinValues), len(withinFields))
+	}
+	place(withinFields, withinValues, false)
+
+	got := new(big.Int).SetBytes([]byte{byte(octets[0]), byte(octets[1]), byte(octets[2]), byte(octets[3])})
+	if got.Cmp(&want) != 0 {
+		expected := "0x" + want.Text(16)
+		if want.BitLen() <= 32 {
+			expected = formatIPv4(uint32(want.Uint64()))
+		}
+		return fmt.Errorf("verify: packed %d.%d.%d.%d, but the fields and within give %s",
+			octets[0], octets[1], octets[2], octets[3], expected)
+	}
+
+	return nil
+}
+
+// cross-check a result against the standard library: with net.ParseCIDR
+// building the within's network from its prefix length, the result must
+// lie inside it.  a within without a prefix fixes no bits, so any result
+// does.
+func verifyResult(octets, within []int, fixed int) error {
+	if len(octets) != 4 || len(within) != 4 {
+		return fmt.Errorf("verify: expected 4 octets, found %d and %d", len(octets), len(within))
+	}
+	if fixed < 0 {
+		return nil
+	}
+
+	str := fmt.Sprintf("%d.%d.%d.%d/%d", within[0], within[1], within[2], within[3], fixed)
+	_, ipnet, err := net.ParseCIDR(str)
+	if err != nil {
+		return fmt.Errorf("verify: the standard library rejected %s -- %s", str, err)
+	}
+
+	ip := net.IPv4(byte(octets[0]), byte(octets[1]), byte(octets[2]), byte(octets[3]))
+	if !ipnet.Contains(ip) {
+		return fmt.Errorf("verify: the standard library places %s outside %s", ip, ipnet)
+	}
+
+	return nil
+}
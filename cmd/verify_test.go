@@ -0,0 +1,104 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestVerifyAcceptsCorrectPacking(t *testing.T) {
+	tests := []struct {
+		value, mask, within string
+		opts                translateOptions
+		want                string
+	}{
+		{"1.2.3.4", "8.8.8.8", "0.0.0.0", translateOptions{}, "1.2.3.4"},
+		{"1.1.1", "16.8.8", "172.16.0.0", translateOptions{}, "172.17.1.1"},
+		{"1.2.3.4", "8.8.8.8", "0.0.0.0", translateOptions{lsbFirst: true}, "4.3.2.1"},
+		{"5.6", "12.20", "10.0.0.0", translateOptions{}, "10.80.0.6"},
+		{"1.2", "16.16", "10.0", translateOptions{withinMask: []int{16, 16}}, "0.11.0.2"},
+	}
+
+	for _, tt := range tests {
+		tt.opts.verify = true
+		got, err := translate(tt.value, tt.mask, tt.within, tt.opts)
+		if err != nil {
+			t.Errorf("translate(%q, %q, %q) failed verification: %s", tt.value, tt.mask, tt.within, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("translate(%q, %q, %q) = %s, want %s", tt.value, tt.mask, tt.within, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyCatchesPackingBug(t *testing.T) {
+	// pack correctly, then flip the lowest bit of the last octet
+	broken := func(fields, values []int, lsbFirst bool, trace io.Writer) ([]int, error) {
+		octets, err := computeCIDR(fields, values, lsbFirst, trace)
+		if err != nil {
+			return nil, err
+		}
+		octets[3] ^= 1
+		return octets, nil
+	}
+
+	if _, err := translate("1.1.1", "16.8.8", "172.16.0.0", translateOptions{pack: broken}); err != nil {
+		t.Fatalf("without --verify the broken packing should go unnoticed, got %s", err)
+	}
+
+	_, err := translate("1.1.1", "16.8.8", "172.16.0.0", translateOptions{pack: broken, verify: true})
+	if err == nil {
+		t.Fatal("--verify accepted a broken packing")
+	}
+	if !strings.Contains(err.Error(), "172.17.1.1") {
+		t.Errorf("expected the error to name the recomputed 172.17.1.1, got %s", err)
+	}
+}
+
+func TestVerifyResult(t *testing.T) {
+	tests := []struct {
+		octets, within []int
+		fixed          int
+		want           string // a substring of the error, or empty
+	}{
+		{[]int{172, 17, 1, 1}, []int{172, 16, 0, 0}, 12, ""},
+		{[]int{172, 31, 255, 255}, []int{172, 16, 0, 0}, 12, ""},
+		{[]int{172, 32, 0, 1}, []int{172, 16, 0, 0}, 12, "places 172.32.0.1 outside 172.16.0.0/12"},
+		{[]int{11, 0, 0, 1}, []int{10, 0, 0, 0}, 8, "outside 10.0.0.0/8"},
+		// no prefix fixes no bits
+		{[]int{11, 0, 0, 1}, []int{10, 0, 0, 0}, -1, ""},
+		{[]int{1, 2, 3}, []int{10, 0, 0, 0}, 8, "expected 4 octets"},
+	}
+
+	for _, tt := range tests {
+		err := verifyResult(tt.octets, tt.within, tt.fixed)
+		if len(tt.want) == 0 {
+			if err != nil {
+				t.Errorf("verifyResult(%v, %v, %d): %s", tt.octets, tt.within, tt.fixed, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("verifyResult(%v, %v, %d) = %v, want %q", tt.octets, tt.within, tt.fixed, err, tt.want)
+		}
+	}
+
+	got, err := translate("1.1.1", "16.8.8", "172.16.0.0/12", translateOptions{verify: true})
+	if err != nil || got != "172.17.1.1" {
+		t.Errorf("translate within a prefix with --verify = %q, %v, want 172.17.1.1", got, err)
+	}
+}
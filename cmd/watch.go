@@ -0,0 +1,127 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch --fifo <path>",
+	Short: "translate rows from a named pipe as they arrive, until stopped",
+	Long: `Read rows of value<TAB>mask<TAB>within from a named pipe and print
+the result of each as it arrives, like process --tsv.  Rather than stop
+at the end of its input, watch waits for the next writer to open the
+pipe, so it runs until interrupted (SIGINT or SIGTERM).  Rows which
+can't be translated are reported on stderr.  The bare command's
+translation flags apply to every row, as they do for process.  Example:
+
+	mkfifo /tmp/in
+	cidr watch --fifo /tmp/in &
+	printf '0.1.1.1\t12.8.6.6\t172.16.0.0\n' > /tmp/in
+
+returns
+
+	172.16.16.65
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		path, err := cmd.Flags().GetString("fifo")
+		if err != nil {
+			panic(err)
+		}
+		if len(path) == 0 {
+			cmd.Usage()
+			return
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+		if info.Mode()&os.ModeNamedPipe == 0 {
+			fmt.Printf("'%s' is not a named pipe; create one with mkfifo\n", path)
+			os.Exit(1)
+		}
+
+		opts, family, err := processOptionsFromFlags(cmd)
+		if err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if err := watchFIFO(ctx, path, os.Stdout, os.Stderr, opts, family); err != nil {
+			fmt.Printf("%s\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+// openFIFO opens the fifo, blocking until a writer connects; a variable
+// so a test can connect writers through pipes
+var openFIFO = func(path string) (io.ReadCloser, error) {
+	return os.Open(path)
+}
+
+// translate the rows written to the fifo with opts, reopening it
+// whenever its last writer closes it, until ctx is done.  opening a fifo
+// blocks until a writer connects, so the reads run in their own
+// goroutine, which is abandoned on shutdown.
+func watchFIFO(ctx context.Context, path string, out, errs io.Writer, opts translateOptions, family string) error {
+	done := make(chan error, 1)
+
+	go func() {
+		for {
+			f, err := openFIFO(path)
+			if err != nil {
+				done <- err
+				return
+			}
+			logger.Info("writer connected", "fifo", path)
+
+			// a malformed row ends this writer's input, not the watch
+			if _, err := processTSV(f, out, errs, opts, family); err != nil {
+				fmt.Fprintf(errs, "%s\n", err)
+			}
+			f.Close()
+			logger.Info("writers closed the fifo; waiting for the next", "fifo", path)
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		logger.Info("stopping", "fifo", path)
+		return nil
+	case err := <-done:
+		return err
+	}
+}
+
+func init() {
+	RootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().String("fifo", "", "the named pipe to read rows from")
+}
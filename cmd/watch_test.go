@@ -0,0 +1,146 @@
+// Copyright © 2017 Mike Hudgins <mchudgins@gmail.com>
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// a buffer safe to write from the watch's goroutine
+type lockedBuffer struct {
+	mu sync.Mutex
+	sb strings.Builder
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sb.Write(p)
+}
+
+func (b *lockedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.sb.String()
+}
+
+func TestWatchContinuousInput(t *testing.T) {
+	// each open of the fifo connects the next writer.  the watch
+	// abandons its reading goroutine, so at the end of the test it is
+	// told to quit, and waited for before openFIFO is put back.
+	readers := make(chan io.ReadCloser)
+	quit, exited := make(chan struct{}), make(chan struct{})
+
+	saved := openFIFO
+	t.Cleanup(func() { openFIFO = saved })
+	t.Cleanup(func() {
+		close(quit)
+		select {
+		case <-exited:
+		case <-time.After(5 * time.Second):
+			t.Error("the watch's reader didn't exit")
+		}
+	})
+	openFIFO = func(path string) (io.ReadCloser, error) {
+		select {
+		case r := <-readers:
+			return r, nil
+		case <-quit:
+			close(exited)
+			return nil, errors.New("test over")
+		}
+	}
+
+	outR, outW := io.Pipe()
+	results := bufio.NewReader(outR)
+	var errs lockedBuffer
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- watchFIFO(ctx, "fifo", outW, &errs, translateOptions{}, "auto") }()
+
+	// connect a writer, returning its end of the pipe
+	connect := func() *os.File {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		readers <- r
+		return w
+	}
+	// write a row and wait for its result, which must arrive while the
+	// writer is still connected
+	send := func(w *os.File, row, want string) {
+		t.Helper()
+		if _, err := io.WriteString(w, row+"\n"); err != nil {
+			t.Fatal(err)
+		}
+		got, err := results.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want+"\n" {
+			t.Errorf("the row %q gave %q, want %s", row, got, want)
+		}
+	}
+
+	w := connect()
+	send(w, "0.1.1.1\t12.8.6.6\t172.16.0.0", "172.16.16.65")
+	send(w, "1.2.3.4\t8.8.8.8", "1.2.3.4")
+	w.Close()
+
+	// the watch outlives its first writer, and reports a bad row
+	// without stopping
+	w = connect()
+	io.WriteString(w, "1.2.3\t8.8.8.8\t0.0.0.0\n")
+	send(w, "0.1\t16.16\t10.0.0.0", "10.0.0.1")
+	w.Close()
+	if !strings.Contains(errs.String(), "mask defines 4 fields but value only provides 3") {
+		t.Errorf("the bad row was reported as %q", errs.String())
+	}
+
+	w = connect()
+	send(w, "0.1.1\t16.8.8\t10.0.0.0", "10.0.1.1")
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("watch stopped with %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch didn't stop when its context was cancelled")
+	}
+	w.Close()
+}
+
+func TestWatchOpenError(t *testing.T) {
+	saved := openFIFO
+	defer func() { openFIFO = saved }()
+	openFIFO = func(path string) (io.ReadCloser, error) {
+		return nil, errors.New("no such fifo")
+	}
+
+	if err := watchFIFO(context.Background(), "fifo", io.Discard, io.Discard, translateOptions{}, "auto"); err == nil || err.Error() != "no such fifo" {
+		t.Errorf("watchFIFO = %v, want the open error", err)
+	}
+}